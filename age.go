@@ -0,0 +1,340 @@
+package jess
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/decred/dcrd/bech32"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/safing/jess/tools"
+)
+
+// Prefixes and headers of the age format.
+const (
+	AgeRecipientPrefix = "age1"
+	AgeIdentityPrefix  = "AGE-SECRET-KEY-1"
+	AgeFileHeader      = "age-encryption.org/v1\n"
+
+	ageRecipientHRP = "age"
+	ageIdentityHRP  = "age-secret-key-"
+	ageKeySize      = 32
+	ageSignetScheme = "ECDH-X25519"
+)
+
+// SignetFromAgeRecipient imports an age X25519 recipient ("age1...") as an ECDH-X25519 recipient.
+func SignetFromAgeRecipient(recipient string) (*Signet, error) {
+	pubKey, err := decodeAgeKey(recipient, AgeRecipientPrefix, ageRecipientHRP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age recipient: %w", err)
+	}
+	return newAgeSignet(pubKey, nil)
+}
+
+// SignetFromAgeIdentity imports an age X25519 identity ("AGE-SECRET-KEY-1...") as an ECDH-X25519 signet.
+func SignetFromAgeIdentity(identity string) (*Signet, error) {
+	privKey, err := decodeAgeKey(identity, AgeIdentityPrefix, ageIdentityHRP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity: %w", err)
+	}
+
+	// derive public key
+	derived, err := curve25519.X25519(privKey[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity: %w", err)
+	}
+	var pubKey [ageKeySize]byte
+	copy(pubKey[:], derived)
+
+	return newAgeSignet(pubKey, &privKey)
+}
+
+// decodeAgeKey decodes a bech32 encoded age key.
+// The prefix is checked including its case, as age only uses lowercase recipients and uppercase identities.
+func decodeAgeKey(s, expectedPrefix, expectedHRP string) (key [ageKeySize]byte, err error) {
+	if !strings.HasPrefix(s, expectedPrefix) {
+		return key, fmt.Errorf("missing prefix %q", expectedPrefix)
+	}
+	hrp, data, err := bech32.DecodeToBase256(s)
+	switch {
+	case err != nil:
+		return key, err
+	case hrp != expectedHRP:
+		return key, fmt.Errorf("unexpected type %q", hrp)
+	case len(data) != ageKeySize:
+		return key, fmt.Errorf("key must be %d bytes", ageKeySize)
+	}
+
+	copy(key[:], data)
+	return key, nil
+}
+
+func newAgeSignet(pubKey [ageKeySize]byte, privKey *[ageKeySize]byte) (*Signet, error) {
+	tool, err := tools.Get(ageSignetScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	signet := NewSignetBase(tool)
+	if privKey != nil {
+		signet.SetLoadedKeys(pubKey, *privKey)
+	} else {
+		signet.SetLoadedKeys(pubKey, nil)
+		signet.Public = true
+	}
+	err = signet.StoreKey()
+	if err != nil {
+		return nil, err
+	}
+	err = signet.AssignUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return signet, nil
+}
+
+// AgeRecipient returns the age X25519 recipient ("age1...") of an ECDH-X25519 signet.
+func (signet *Signet) AgeRecipient() (string, error) {
+	if signet.Scheme != ageSignetScheme {
+		return "", fmt.Errorf("only %s signets can be used with age", ageSignetScheme)
+	}
+	err := signet.LoadKey()
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, ok := signet.PublicKey().([ageKeySize]byte)
+	if !ok {
+		return "", tools.ErrInvalidKey
+	}
+	return bech32.EncodeFromBase256(ageRecipientHRP, pubKey[:])
+}
+
+// ageIdentity returns the age X25519 identity of an ECDH-X25519 signet.
+func (signet *Signet) ageIdentity() (*age.X25519Identity, error) {
+	if signet.Scheme != ageSignetScheme {
+		return nil, fmt.Errorf("only %s signets can be used with age", ageSignetScheme)
+	}
+	err := signet.LoadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, ok := signet.PrivateKey().([ageKeySize]byte)
+	if !ok {
+		return nil, tools.ErrInvalidKey
+	}
+	identity, err := bech32.EncodeFromBase256(ageIdentityHRP, privKey[:])
+	if err != nil {
+		return nil, err
+	}
+	return age.ParseX25519Identity(strings.ToUpper(identity))
+}
+
+// IsAgeFormat returns whether the data is an age file, either binary or armored.
+func IsAgeFormat(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(AgeFileHeader)) ||
+		bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header))
+}
+
+// CloseAge encrypts the data for the recipients and the password of the envelope and returns it in the binary age v1 format.
+// The envelope must use a suite that matches the age format: SuiteRcptOnlyV1 for recipients, which must be ECDH-X25519 signets, or SuitePasswordV1 for a password.
+// Senders and keys are not supported by the age format.
+func (e *Envelope) CloseAge(trustStore TrustStore, data []byte) ([]byte, error) {
+	if len(e.Senders) > 0 {
+		return nil, errors.New("the age format does not support senders")
+	}
+	err := e.LoadSuite()
+	if err != nil {
+		return nil, err
+	}
+	switch e.suite.ID {
+	case SuiteRcptOnlyV1:
+		if len(e.Secrets) > 0 {
+			return nil, fmt.Errorf("suite %s does not support passwords", e.suite.ID)
+		}
+	case SuitePasswordV1:
+		if len(e.Recipients) > 0 {
+			return nil, fmt.Errorf("suite %s does not support recipients", e.suite.ID)
+		}
+	default:
+		return nil, fmt.Errorf("suite %s is not compatible with the age format, use %s or %s", e.suite.ID, SuiteRcptOnlyV1, SuitePasswordV1)
+	}
+	err = e.PrepareSignets(trustStore)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect recipients
+	recipients := make([]age.Recipient, 0, len(e.Secrets)+len(e.Recipients))
+	for _, signet := range e.Secrets {
+		if signet.Scheme != SignetSchemePassword {
+			return nil, errors.New("the age format does not support keys")
+		}
+		if len(signet.Key) == 0 {
+			return nil, fmt.Errorf("missing password for %s", signet.ID)
+		}
+		recipient, err := age.NewScryptRecipient(string(signet.Key))
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	for _, signet := range e.Recipients {
+		ageRecipient, err := signet.AgeRecipient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to use recipient %s: %w", signet.ID, err)
+		}
+		recipient, err := age.ParseX25519Recipient(ageRecipient)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("missing recipients or password")
+	}
+
+	// encrypt
+	buf := &bytes.Buffer{}
+	w, err := age.Encrypt(buf, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// OpenAge decrypts data in the age v1 format, which may also be armored.
+// All private ECDH-X25519 signets of the trust store are tried as identities, if the trust store supports selecting signets.
+// Password encrypted files are opened with the password from the password callback.
+func OpenAge(data []byte, trustStore TrustStore) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(data)
+	if !bytes.HasPrefix(data, []byte(AgeFileHeader)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+
+	// Collect identities, unprotected signets first.
+	var identities, protected []age.Identity
+	if selector, ok := trustStore.(signetSelector); ok {
+		signets, err := selector.SelectSignets(FilterSignetOnly, ageSignetScheme)
+		if err != nil {
+			return nil, err
+		}
+		for _, signet := range signets {
+			identity := &ageSignetIdentity{
+				signet:     signet,
+				trustStore: trustStore,
+			}
+			switch {
+			case signet.Protection != nil:
+				protected = append(protected, identity)
+			case signet.LoadKey() != nil:
+				// Skip unloadable signets, so that they do not abort decryption.
+				continue
+			default:
+				identities = append(identities, identity)
+			}
+		}
+	}
+	identities = append(identities, protected...)
+	identities = append(identities, &agePasswordIdentity{})
+
+	// decrypt
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// signetSelector is implemented by trust stores that can select signets, such as the MemTrustStore.
+type signetSelector interface {
+	SelectSignets(filter uint8, schemes ...string) ([]*Signet, error)
+}
+
+// ageSignetIdentity is an age identity backed by an ECDH-X25519 signet.
+// Protected signets are only unprotected when there is an X25519 stanza to unwrap.
+// As X25519 stanzas do not reference their recipient, failing to unprotect or load
+// a signet is reported as age.ErrIncorrectIdentity, so that other identities are still tried.
+type ageSignetIdentity struct {
+	signet     *Signet
+	trustStore TrustStore
+}
+
+// Unwrap implements the age.Identity interface.
+func (id *ageSignetIdentity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	if !hasAgeStanza(stanzas, "X25519") {
+		return nil, age.ErrIncorrectIdentity
+	}
+
+	signet := id.signet
+	if signet.Protection != nil {
+		// Unprotect a copy, so that the protected signet is not modified.
+		unprotected := *signet
+		err := unprotected.Unprotect(id.trustStore)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to unprotect signet "%s": %w: %w`, signet.ID, age.ErrIncorrectIdentity, err)
+		}
+		signet = &unprotected
+	}
+
+	identity, err := signet.ageIdentity()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to use signet "%s": %w: %w`, signet.ID, age.ErrIncorrectIdentity, err)
+	}
+	return identity.Unwrap(stanzas)
+}
+
+// agePasswordIdentity is an age identity that asks for the password with the password callback.
+type agePasswordIdentity struct{}
+
+// Unwrap implements the age.Identity interface.
+func (id *agePasswordIdentity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	if !hasAgeStanza(stanzas, "scrypt") {
+		return nil, age.ErrIncorrectIdentity
+	}
+	if getPasswordCallback == nil {
+		return nil, errors.New("file is password encrypted, but no password callback is set")
+	}
+
+	signet := &Signet{
+		Version: 1,
+		Scheme:  SignetSchemePassword,
+		Info: &SignetInfo{
+			Name: "age file",
+		},
+	}
+	err := getPasswordCallback(signet)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := age.NewScryptIdentity(string(signet.Key))
+	if err != nil {
+		return nil, err
+	}
+	return identity.Unwrap(stanzas)
+}
+
+func hasAgeStanza(stanzas []*age.Stanza, stanzaType string) bool {
+	for _, stanza := range stanzas {
+		if stanza.Type == stanzaType {
+			return true
+		}
+	}
+	return false
+}
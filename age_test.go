@@ -0,0 +1,320 @@
+package jess
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func TestAgeKeyImport(t *testing.T) {
+	t.Parallel()
+
+	ageIdentity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// import identity
+	signet, err := SignetFromAgeIdentity(ageIdentity.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signet.Public || signet.Scheme != "ECDH-X25519" {
+		t.Fatalf("unexpected signet: public=%v scheme=%s", signet.Public, signet.Scheme)
+	}
+	identity, err := signet.ageIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.String() != ageIdentity.String() {
+		t.Fatal("identity does not match after import")
+	}
+
+	// import recipient
+	recipient, err := SignetFromAgeRecipient(ageIdentity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recipient.Public {
+		t.Fatal("recipient should be public")
+	}
+
+	// both must result in the same public key
+	for _, s := range []*Signet{signet, recipient} {
+		ageRecipient, err := s.AgeRecipient()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ageRecipient != ageIdentity.Recipient().String() {
+			t.Fatalf("recipient %s does not match %s", ageRecipient, ageIdentity.Recipient())
+		}
+	}
+
+	// imported signets must survive serialization
+	data, err := signet.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := SignetFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity, err = loaded.ageIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.String() != ageIdentity.String() {
+		t.Fatal("identity does not match after serialization")
+	}
+
+	// check invalid input
+	for _, invalid := range []string{
+		"",
+		"age1",
+		ageIdentity.String(),
+		strings.ToUpper(ageIdentity.Recipient().String()),
+		ageIdentity.Recipient().String()[:40],
+		ageIdentity.Recipient().String() + "q",
+	} {
+		_, err := SignetFromAgeRecipient(invalid)
+		if err == nil {
+			t.Errorf("recipient %q should be invalid", invalid)
+		}
+	}
+	_, err = SignetFromAgeIdentity(ageIdentity.Recipient().String())
+	if err == nil {
+		t.Error("recipient should not be accepted as identity")
+	}
+}
+
+func TestAgeInterop(t *testing.T) {
+	t.Parallel()
+
+	ageIdentity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet, err := SignetFromAgeIdentity(ageIdentity.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := SignetFromAgeRecipient(ageIdentity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient.ID = signet.ID
+
+	// a second key, which must not be able to open the file
+	otherSignet, err := GenerateSignet("ECDH-X25519", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = otherSignet.StoreKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = otherSignet.AssignUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unloadable key, which must be skipped
+	brokenSignet := &Signet{
+		Version: 1,
+		ID:      "broken",
+		Scheme:  "ECDH-X25519",
+		Key:     []byte{1, 2, 3},
+	}
+
+	trustStore := NewMemTrustStore()
+	for _, s := range []*Signet{signet, recipient, otherSignet, brokenSignet} {
+		err = trustStore.StoreSignet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// close with jess, open with age
+	envelope := NewUnconfiguredEnvelope()
+	envelope.SuiteID = SuiteRcptOnly
+	envelope.Recipients = []*Signet{{ID: signet.ID}}
+	closed, err := envelope.CloseAge(trustStore, []byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAgeFormat(closed) {
+		t.Fatal("closed data should be detected as age")
+	}
+	r, err := age.Decrypt(bytes.NewReader(closed), ageIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != testData1 {
+		t.Fatal("data opened by age does not match")
+	}
+
+	// close with age, open with jess
+	buf := &bytes.Buffer{}
+	armorWriter := armor.NewWriter(buf)
+	w, err := age.Encrypt(armorWriter, ageIdentity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Write([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = armorWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAgeFormat(buf.Bytes()) {
+		t.Fatal("armored data should be detected as age")
+	}
+	opened, err = OpenAge(buf.Bytes(), trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != testData1 {
+		t.Fatal("data opened by jess does not match")
+	}
+
+	// without the identity, the file cannot be opened
+	err = trustStore.DeleteSignet(signet.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = OpenAge(buf.Bytes(), trustStore)
+	if err == nil {
+		t.Fatal("opening without the identity should fail")
+	}
+
+	// suites other than the age compatible ones are rejected
+	incompatible := NewUnconfiguredEnvelope()
+	incompatible.SuiteID = SuiteComplete
+	incompatible.Recipients = []*Signet{{ID: otherSignet.ID}}
+	_, err = incompatible.CloseAge(trustStore, []byte(testData1))
+	if err == nil || !strings.Contains(err.Error(), "not compatible with the age format") {
+		t.Fatalf("closing with an incompatible suite should fail, got: %v", err)
+	}
+
+	// senders are not supported
+	envelope.Senders = []*Signet{otherSignet}
+	_, err = envelope.CloseAge(trustStore, []byte(testData1))
+	if err == nil {
+		t.Fatal("closing with a sender should fail")
+	}
+}
+
+func TestAgeProtectedSignets(t *testing.T) {
+	t.Parallel()
+
+	trustStore := NewMemTrustStore()
+
+	// create two protected signets, where only the second is a recipient
+	protectedSignets := make([]*Signet, 0, 2)
+	for _, id := range []string{"test-age-protected-other", "test-age-protected-recipient"} {
+		protectionKey := &Signet{
+			Version: 1,
+			ID:      id + "-key",
+			Scheme:  SignetSchemeKey,
+		}
+		var err error
+		protectionKey.Key, err = RandomBytes(32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = trustStore.StoreSignet(protectionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signet, err := GenerateSignet("ECDH-X25519", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signet.ID = id
+		err = signet.StoreKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipient, err := signet.AsRecipient()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = recipient.StoreKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = trustStore.StoreSignet(recipient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = signet.Protect(&Envelope{
+			Version: 1,
+			SuiteID: SuiteKey,
+			Secrets: []*Signet{{ID: protectionKey.ID, Scheme: SignetSchemeKey}},
+		}, trustStore)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = trustStore.StoreSignet(signet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		protectedSignets = append(protectedSignets, signet)
+	}
+
+	// The first signet cannot be unprotected, like when the user cancels the password prompt.
+	err := trustStore.DeleteSignet(protectedSignets[0].ID+"-key", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// close for the second signet
+	envelope := NewUnconfiguredEnvelope()
+	envelope.SuiteID = SuiteRcptOnly
+	envelope.Recipients = []*Signet{{ID: protectedSignets[1].ID}}
+	closed, err := envelope.CloseAge(trustStore, []byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Failing to unprotect the first signet must not stop decryption.
+	r, err := age.Decrypt(
+		bytes.NewReader(closed),
+		&ageSignetIdentity{signet: protectedSignets[0], trustStore: trustStore},
+		&ageSignetIdentity{signet: protectedSignets[1], trustStore: trustStore},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != testData1 {
+		t.Fatal("data opened with protected signet does not match")
+	}
+
+	opened, err = OpenAge(closed, trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != testData1 {
+		t.Fatal("data opened by jess does not match")
+	}
+}
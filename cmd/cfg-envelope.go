@@ -32,6 +32,7 @@ func newEnvelope(name string) (*jess.Envelope, error) {
 			"Encrypt for someone but don't sign",
 			"Sign a file (wrapped)",
 			"Sign a file (separate sig)",
+			"Sign a file (separate sig, post-quantum hybrid)",
 		},
 	}
 	err := survey.AskOne(prompt, &preset, nil)
@@ -61,6 +62,9 @@ func newEnvelope(name string) (*jess.Envelope, error) {
 	case "Sign a file (separate sig)":
 		envelope.SuiteID = jess.SuiteSignFile
 		err = selectSignets(envelope, "sender")
+	case "Sign a file (separate sig, post-quantum hybrid)":
+		envelope.SuiteID = jess.SuiteSignFilePQV1
+		err = selectSignets(envelope, "sender")
 	}
 	if err != nil {
 		return nil, err
@@ -96,6 +100,9 @@ func editEnvelope(envelope *jess.Envelope) error {
 				{"Secrets", formatSignetNames(envelope.Secrets)},
 				{"Recipients", formatSignetNames(envelope.Recipients)},
 				{"Senders", formatSignetNames(envelope.Senders)},
+				{"Padding", formatSuiteDefault(envelope.Padding)},
+				{"Compression", formatSuiteDefault(envelope.Compression)},
+				{"Key Wrapping", formatKeyWrapping(envelope.WrapKey)},
 				{""},
 				{"Export", "export to text format"},
 				{"Abort", "discard changes and return"},
@@ -144,6 +151,12 @@ func editEnvelope(envelope *jess.Envelope) error {
 			err = selectSignets(envelope, "recipient")
 		case strings.HasPrefix(submenu, "Senders"):
 			err = selectSignets(envelope, "sender")
+		case strings.HasPrefix(submenu, "Padding"):
+			err = editEnvelopePadding(envelope)
+		case strings.HasPrefix(submenu, "Compression"):
+			err = editEnvelopeCompression(envelope)
+		case strings.HasPrefix(submenu, "Key Wrapping"):
+			envelope.WrapKey = !envelope.WrapKey
 		}
 		if err != nil {
 			return err
@@ -178,3 +191,48 @@ func editEnvelopeSuite(envelope *jess.Envelope) error {
 	envelope.SuiteID = strings.Fields(selectedSuite)[0]
 	return envelope.ReloadSuite()
 }
+
+func editEnvelopePadding(envelope *jess.Envelope) error {
+	prompt := &survey.Input{
+		Message: "Padding policy (eg. NONE, PADME, BLOCK(256), BUCKETS(64,256,1024)), empty for suite default:",
+		Default: envelope.Padding,
+	}
+	return survey.AskOne(prompt, &envelope.Padding, nil)
+}
+
+func editEnvelopeCompression(envelope *jess.Envelope) error {
+	var selected string
+	prompt := &survey.Select{
+		Message: "Select compression",
+		Options: formatColumns([][]string{
+			{"Suite default"},
+			{jess.CompressionNone},
+			{jess.CompressionDeflate},
+		}),
+	}
+	err := survey.AskOne(prompt, &selected, nil)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(selected, "Suite default") {
+		envelope.Compression = ""
+	} else {
+		envelope.Compression = strings.TrimSpace(selected)
+	}
+	return nil
+}
+
+func formatKeyWrapping(wrapKey bool) string {
+	if wrapKey {
+		return "enabled, recipients can be replaced with jess rewrap"
+	}
+	return "disabled"
+}
+
+func formatSuiteDefault(value string) string {
+	if value == "" {
+		return "suite default"
+	}
+	return value
+}
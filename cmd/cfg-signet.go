@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 )
 
 //nolint:gocognit
-func newSignet(name, scheme string, saveToTrustStore bool) (*jess.Signet, error) {
+func newSignet(name, scheme string, saveToTrustStore, protect bool) (*jess.Signet, error) {
 	// get name
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -33,7 +34,15 @@ func newSignet(name, scheme string, saveToTrustStore bool) (*jess.Signet, error)
 			{jess.SignetSchemePassword, "Password"},
 			{jess.SignetSchemeKey, "Key", "dynamic b/s (set manually via --symkeysize)"},
 			{"ECDH-X25519", "Receiving (KeyExchange)"},
+			{"ECDH-X448", "Receiving (KeyExchange, high-security)"},
+			{"ML-KEM-768", "Receiving (KeyEncapsulation, post-quantum)"},
+			{"HPKE-X25519", "Receiving (KeyEncapsulation, HPKE)"},
+			{"HPKE-AUTH-X25519", "Sending and Receiving (KeyEncapsulation, HPKE auth mode)"},
 			{"Ed25519", "Signing"},
+			{"Ed448", "Signing (high-security)"},
+			{"ML-DSA-65", "Signing (post-quantum)"},
+			{"SLH-DSA-SHA2-128s", "Signing (post-quantum, hash-based)"},
+			{"ECDSA-P256", "Signing (FIPS)"},
 		}
 
 		// select scheme
@@ -111,12 +120,6 @@ func newSignet(name, scheme string, saveToTrustStore bool) (*jess.Signet, error)
 	}
 
 	if saveToTrustStore {
-		// write signet
-		err = trustStore.StoreSignet(signet)
-		if err != nil {
-			return nil, err
-		}
-
 		// export as recipient
 		switch scheme {
 		case jess.SignetSchemePassword, jess.SignetSchemeKey:
@@ -135,11 +138,44 @@ func newSignet(name, scheme string, saveToTrustStore bool) (*jess.Signet, error)
 				return nil, err
 			}
 		}
+
+		// protect signet, after the recipient was derived
+		if protect {
+			err = protectSignet(signet)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// write signet
+		err = trustStore.StoreSignet(signet)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return signet, nil
 }
 
+// protectSignet protects the signet with a password the user is asked for.
+func protectSignet(signet *jess.Signet) error {
+	err := signet.Protect(&jess.Envelope{
+		Version: 1,
+		SuiteID: jess.SuitePassword,
+		Secrets: []*jess.Signet{{
+			Version: 1,
+			Scheme:  jess.SignetSchemePassword,
+			Info: &jess.SignetInfo{
+				Name: fmt.Sprintf("protection of %s", formatSignetName(signet)),
+			},
+		}},
+	}, trustStore)
+	if err != nil {
+		return fmt.Errorf("failed to protect signet: %w", err)
+	}
+	return nil
+}
+
 func selectSignets(envelope *jess.Envelope, scope string) error {
 	// collect all signet schemes that fit the scope
 	var schemes []string
@@ -171,7 +207,8 @@ func selectSignets(envelope *jess.Envelope, scope string) error {
 	case "sender":
 		promptMsg = "Select senders: (selection is AND, not OR!)"
 		for _, tool := range tools.AsList() {
-			if tool.Info.Purpose == tools.PurposeSigning {
+			if tool.Info.Purpose == tools.PurposeSigning ||
+				tool.Info.HasOption(tools.OptionAuthenticatesSender) {
 				schemes = append(schemes, tool.Info.Name)
 			}
 		}
@@ -9,15 +9,20 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/safing/jess"
+	"github.com/safing/structures/container"
 )
 
 func init() {
 	rootCmd.AddCommand(closeCmd)
 	closeCmd.Flags().StringVarP(&closeFlagOutput, "output", "o", "", "specify output file (`-` for stdout)")
+	closeCmd.Flags().StringVar(&closeFlagFormat, "format", formatLetter, "specify output format: letter or age")
 }
 
 var (
 	closeFlagOutput string
+	closeFlagFormat string
 	closeCmdHelp    = "usage: jess close <file> with <envelope name>"
 
 	closeCmd = &cobra.Command{
@@ -34,6 +39,17 @@ var (
 				return errors.New(closeCmdHelp)
 			}
 
+			// check format
+			var fileExtension string
+			switch closeFlagFormat {
+			case formatLetter:
+				fileExtension = letterFileExtension
+			case formatAge:
+				fileExtension = ageFileExtension
+			default:
+				return fmt.Errorf("unknown format %q", closeFlagFormat)
+			}
+
 			// get envelope
 			envelope, err := trustStore.GetEnvelope(args[2])
 			if err != nil {
@@ -41,19 +57,22 @@ var (
 			}
 
 			// create session (check envelope)
-			session, err := envelope.Correspondence(trustStore)
-			if err != nil {
-				return err
+			var session *jess.Session
+			if closeFlagFormat == formatLetter {
+				session, err = envelope.Correspondence(trustStore)
+				if err != nil {
+					return err
+				}
 			}
 
 			// check filenames
 			filename := args[0]
 			outputFilename := closeFlagOutput
 			if outputFilename == "" {
-				if strings.HasSuffix(filename, letterFileExtension) {
+				if strings.HasSuffix(filename, fileExtension) {
 					return errors.New("cannot automatically derive output filename, please specify with --output")
 				}
-				outputFilename = filename + letterFileExtension
+				outputFilename = filename + fileExtension
 			}
 			// check input file
 			if filename != "-" {
@@ -99,15 +118,24 @@ var (
 			}
 
 			// encrypt
-			letter, err := session.Close(data)
-			if err != nil {
-				return err
-			}
+			var c *container.Container
+			if closeFlagFormat == formatAge {
+				ageData, err := envelope.CloseAge(trustStore, data)
+				if err != nil {
+					return err
+				}
+				c = container.New(ageData)
+			} else {
+				letter, err := session.Close(data)
+				if err != nil {
+					return err
+				}
 
-			// to file format
-			c, err := letter.ToFileFormat()
-			if err != nil {
-				return err
+				// to file format
+				c, err = letter.ToFileFormat()
+				if err != nil {
+					return err
+				}
 			}
 
 			// open file for writing
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -11,12 +12,14 @@ func init() {
 	generateCmd.Flags().StringVarP(&generateFlagName, "name", "l", "", "specify signet name/label")
 	generateCmd.Flags().StringVarP(&generateFlagScheme, "scheme", "t", "", "specify signet scheme/tool")
 	generateCmd.Flags().BoolVarP(&generateFlagTextOnly, "textonly", "", false, "do not save to trust store and only output directly as text")
+	generateCmd.Flags().BoolVarP(&generateFlagProtect, "protect", "", false, "protect the private key with a password")
 }
 
 var (
 	generateFlagName     string
 	generateFlagScheme   string
 	generateFlagTextOnly bool
+	generateFlagProtect  bool
 
 	generateCmd = &cobra.Command{
 		Use:                   "generate",
@@ -25,8 +28,12 @@ var (
 		Args:                  cobra.NoArgs,
 		PreRunE:               requireTrustStore,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if generateFlagProtect && generateFlagTextOnly {
+				return errors.New("protected signets must be saved to the trust store")
+			}
+
 			// Generate new signet
-			signet, err := newSignet(generateFlagName, generateFlagScheme, !generateFlagTextOnly)
+			signet, err := newSignet(generateFlagName, generateFlagScheme, !generateFlagTextOnly, generateFlagProtect)
 			if err != nil {
 				return err
 			}
@@ -3,20 +3,31 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/safing/jess"
+	"github.com/safing/jess/filesig"
 )
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(backupCmd)
 	rootCmd.AddCommand(importCmd)
+	exportCmd.Flags().BoolVar(&exportFlagPEM, "pem", false, "export the public key of a signet as PEM (SubjectPublicKeyInfo)")
+	exportCmd.Flags().BoolVar(&exportFlagMinisign, "minisign", false, "export the public key of an Ed25519 signet as minisign public key")
+	backupCmd.Flags().BoolVar(&exportFlagPEM, "pem", false, "backup the private key of a signet as PEM (PKCS#8)")
+	importCmd.Flags().StringVarP(&importFlagScheme, "scheme", "t", "", "specify the signet scheme/tool of PEM keys, required for RSA keys")
 }
 
 var (
+	exportFlagPEM      bool
+	exportFlagMinisign bool
+	importFlagScheme   string
+
 	exportCmdHelp = "usage: export <id>"
 	exportCmd     = &cobra.Command{
 		Use:   "export <id>",
@@ -33,11 +44,11 @@ var (
 		RunE:  handleBackup,
 	}
 
-	importCmdHelp = "usage: import <text>"
+	importCmdHelp = "usage: import <text|file>"
 	importCmd     = &cobra.Command{
-		Use:   "import <text>",
+		Use:   "import <text|file>",
 		Short: "import a signet or an enveleope",
-		Long:  "import a signet (any kind) or an enveleope",
+		Long:  "import a signet (any kind, including age, OpenSSH Ed25519 and PEM keys) or an enveleope, from text or a file",
 		RunE:  handleImport,
 	}
 )
@@ -52,6 +63,12 @@ func handleExport(cmd *cobra.Command, args []string) error {
 	// Get Recipient.
 	recipient, err := trustStore.GetSignet(id, true)
 	if err == nil {
+		if exportFlagPEM {
+			return printPEM(recipient.ExportPEM())
+		}
+		if exportFlagMinisign {
+			return printMinisignPublicKey(recipient)
+		}
 		text, err := recipient.Export(false)
 		if err != nil {
 			return fmt.Errorf("failed to export recipient %s: %w", id, err)
@@ -67,6 +84,12 @@ func handleExport(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed convert signet %s to recipient for export: %w", id, err)
 		}
+		if exportFlagPEM {
+			return printPEM(recipient.ExportPEM())
+		}
+		if exportFlagMinisign {
+			return printMinisignPublicKey(recipient)
+		}
 		text, err := recipient.Export(false)
 		if err != nil {
 			return fmt.Errorf("failed to export recipient %s: %w", id, err)
@@ -98,7 +121,16 @@ func handleBackup(cmd *cobra.Command, args []string) error {
 
 	// Check if there is a signet instead.
 	signet, err := trustStore.GetSignet(id, false)
-	if err != nil {
+	if err == nil {
+		if exportFlagPEM {
+			if signet.Protection != nil {
+				err = signet.Unprotect(trustStore)
+				if err != nil {
+					return fmt.Errorf("failed to unprotect signet %s: %w", id, err)
+				}
+			}
+			return printPEM(signet.BackupPEM())
+		}
 		text, err := signet.Backup(false)
 		if err != nil {
 			return fmt.Errorf("failed to backup signet %s: %w", id, err)
@@ -110,6 +142,23 @@ func handleBackup(cmd *cobra.Command, args []string) error {
 	return errors.New("no signet found with the given ID")
 }
 
+func printMinisignPublicKey(recipient *jess.Signet) error {
+	pubKey, err := filesig.MinisignPublicKey(recipient)
+	if err != nil {
+		return fmt.Errorf("failed to export as minisign public key: %w", err)
+	}
+	fmt.Print(pubKey)
+	return nil
+}
+
+func printPEM(pemData []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to export as PEM: %w", err)
+	}
+	fmt.Print(string(pemData))
+	return nil
+}
+
 func handleImport(cmd *cobra.Command, args []string) error {
 	// Check args.
 	if len(args) != 1 {
@@ -117,6 +166,11 @@ func handleImport(cmd *cobra.Command, args []string) error {
 	}
 	text := args[0]
 
+	// Read the text from a file, if a file path was given.
+	if data, err := os.ReadFile(text); err == nil {
+		text = strings.TrimSpace(string(data))
+	}
+
 	// First, check if it's an envelope.
 	if strings.HasPrefix(text, jess.ExportEnvelopePrefix) {
 		env, err := jess.EnvelopeFromTextFormat(text)
@@ -133,8 +187,9 @@ func handleImport(cmd *cobra.Command, args []string) error {
 
 	// Then handle all signet types together.
 	var (
-		signetType string
-		parseFunc  func(textFormat string) (*jess.Signet, error)
+		signetType     string
+		parseFunc      func(textFormat string) (*jess.Signet, error)
+		storeRecipient bool
 	)
 	switch {
 	case strings.HasPrefix(text, jess.ExportSenderPrefix):
@@ -146,9 +201,31 @@ func handleImport(cmd *cobra.Command, args []string) error {
 	case strings.HasPrefix(text, jess.ExportKeyPrefix):
 		signetType = jess.ExportKeyKeyword
 		parseFunc = jess.KeyFromTextFormat
+	case strings.HasPrefix(text, jess.AgeIdentityPrefix):
+		signetType = "age identity"
+		parseFunc = jess.SignetFromAgeIdentity
+		storeRecipient = true
+	case strings.HasPrefix(text, jess.AgeRecipientPrefix):
+		signetType = "age recipient"
+		parseFunc = jess.SignetFromAgeRecipient
+	case strings.HasPrefix(text, jess.SSHPrivateKeyHeader):
+		signetType = "OpenSSH private key"
+		parseFunc = func(textFormat string) (*jess.Signet, error) {
+			return jess.SignetFromSSHPrivateKey([]byte(textFormat))
+		}
+		storeRecipient = true
+	case jess.IsSSHPublicKey(text):
+		signetType = "OpenSSH public key"
+		parseFunc = jess.SignetFromSSHPublicKey
+	case jess.IsPEM(text):
+		signetType = "PEM key"
+		parseFunc = func(textFormat string) (*jess.Signet, error) {
+			return jess.SignetFromPEM([]byte(textFormat), importFlagScheme)
+		}
+		storeRecipient = strings.HasPrefix(text, "-----BEGIN "+jess.PEMPrivateKeyType)
 	default:
 		return fmt.Errorf(
-			"invalid format or unknown type, expected one of %s, %s, %s, %s",
+			"invalid format or unknown type, expected one of %s, %s, %s, %s, an age key, an OpenSSH key or a PEM key",
 			jess.ExportKeyKeyword,
 			jess.ExportSenderKeyword,
 			jess.ExportRecipientKeyword,
@@ -160,10 +237,62 @@ func handleImport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse %s: %w", signetType, err)
 	}
+	if strings.HasPrefix(text, jess.AgeIdentityPrefix) || strings.HasPrefix(text, jess.AgeRecipientPrefix) {
+		// Name age keys after their recipient, so they are easy to find.
+		ageRecipient, err := signet.AgeRecipient()
+		if err != nil {
+			return err
+		}
+		signet.Info = &jess.SignetInfo{
+			Name:    ageRecipient,
+			Created: time.Now(),
+		}
+	}
+	if signet.Info == nil && jess.IsPEM(text) {
+		// Name PEM keys after their scheme, as they have no name.
+		signet.Info = &jess.SignetInfo{
+			Name:    signet.Scheme + " PEM key",
+			Created: time.Now(),
+		}
+	}
+	if signet.Info == nil && strings.HasPrefix(text, jess.SSHPrivateKeyHeader) {
+		// Name OpenSSH private keys after their public key, as they have no comment.
+		sshPubKey, err := signet.SSHPublicKey()
+		if err != nil {
+			return err
+		}
+		signet.Info = &jess.SignetInfo{
+			Name:    sshPubKey,
+			Created: time.Now(),
+		}
+	}
+	if signet.Signature != nil {
+		report, err := signet.VerifyDetailed(trustStore)
+		if err != nil {
+			fmt.Printf("warning: %s\n", err)
+		} else {
+			fmt.Printf("%s %s is certified by %s\n", signetType, signet.ID, strings.Join(report.VerifiedSigners(), ", "))
+		}
+	}
 	err = trustStore.StoreSignet(signet)
 	if err != nil {
 		return fmt.Errorf("failed to import %s into trust store: %w", signetType, err)
 	}
+	// Also store the recipient of imported private keys, so that they can be used in envelopes.
+	if storeRecipient {
+		rcpt, err := signet.AsRecipient()
+		if err != nil {
+			return err
+		}
+		err = rcpt.StoreKey()
+		if err != nil {
+			return err
+		}
+		err = trustStore.StoreSignet(rcpt)
+		if err != nil {
+			return fmt.Errorf("failed to import %s into trust store: %w", signetType, err)
+		}
+	}
 	fmt.Printf("imported %s %s intro trust store\n", signetType, signet.ID)
 
 	return nil
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/safing/jess"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
+
+var inspectCmd = &cobra.Command{
+	Use:                   "inspect <file>",
+	Short:                 "show details of a letter without opening it",
+	Long:                  "show details of a letter without opening it. Use `-` to use stdin. The shown details are not verified.",
+	DisableFlagsInUseLine: true,
+	Args:                  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		filename := args[0]
+
+		// load file
+		var data []byte
+		if filename == stdInOutFilename {
+			data, err = io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			if jess.IsLetterStreamFormat(data) {
+				return inspectLetterFile(bytes.NewReader(data))
+			}
+		} else {
+			file, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+
+			// letter streams are described without loading their chunks
+			header := make([]byte, binary.MaxVarintLen64)
+			n, err := file.ReadAt(header, 0)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			if jess.IsLetterStreamFormat(header[:n]) {
+				return inspectLetterFile(file)
+			}
+
+			data, err = io.ReadAll(file)
+			if err != nil {
+				return err
+			}
+		}
+
+		// parse file
+		letter, err := jess.LetterFromFileFormat(container.New(data))
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(formatLetterDescription(letter.Describe(trustStore)))
+		return nil
+	},
+}
+
+func inspectLetterFile(r io.ReaderAt) error {
+	desc, err := jess.DescribeLetterFile(r, trustStore)
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatLetterDescription(desc))
+	return nil
+}
+
+func formatLetterDescription(desc *jess.LetterDescription) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "Version: %d\n", desc.Version)
+	if desc.Suite != nil {
+		fmt.Fprintf(b, "Suite:   %s %s\n", desc.SuiteID, formatSuiteStatus(desc.Suite))
+		fmt.Fprintf(b, "Tools:   %s\n", strings.Join(desc.Suite.Tools, ", "))
+	} else {
+		fmt.Fprintf(b, "Suite:   %s [unknown]\n", desc.SuiteID)
+	}
+
+	if len(desc.Keys) > 0 {
+		b.WriteString("\nKeys:\n")
+		for _, seal := range desc.Keys {
+			fmt.Fprintf(b, "    %s\n", formatSealDescription(seal))
+		}
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(b, "Data:          %d bytes\n", desc.DataSize)
+	if !desc.SignedAt.IsZero() {
+		fmt.Fprintf(b, "Signed At:     %s\n", desc.SignedAt.Format(time.RFC3339))
+	}
+	if desc.Compression != "" {
+		fmt.Fprintf(b, "Compression:   %s\n", desc.Compression)
+	}
+	if desc.Padded {
+		b.WriteString("Padded:        yes\n")
+	}
+	if desc.KeyWrapped {
+		b.WriteString("Key Wrapped:   yes\n")
+	}
+	if desc.Authenticated {
+		b.WriteString("Authenticated: yes\n")
+	} else {
+		b.WriteString("Authenticated: no\n")
+	}
+
+	if len(desc.Signatures) > 0 {
+		fmt.Fprintf(b, "\n%d Signature(s):\n", len(desc.Signatures))
+		for _, seal := range desc.Signatures {
+			if seal.SignedAt.IsZero() {
+				fmt.Fprintf(b, "    %s\n", formatSealDescription(seal))
+			} else {
+				fmt.Fprintf(b, "    %s, signed at %s\n", formatSealDescription(seal), seal.SignedAt.Format(time.RFC3339))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func formatSealDescription(seal *jess.SealDescription) string {
+	var name string
+	switch {
+	case seal.Info != nil && seal.Info.Name != "":
+		name = fmt.Sprintf("%s (%s)", seal.Info.Name, seal.ID)
+	case seal.ID != "":
+		name = seal.ID
+	default:
+		name = "[ephemeral]"
+	}
+
+	switch seal.Scheme {
+	case "":
+		return name
+	case jess.SignetSchemeKey:
+		return "key: " + name
+	case jess.SignetSchemePassword:
+		return "password: " + name
+	default:
+		return seal.Scheme + ": " + name
+	}
+}
@@ -77,13 +77,21 @@ func manageSignets() error {
 		selectedSignet := signets[0]
 
 		// select action
+		actions := []string{"Delete"}
+		switch {
+		case selectedSignet.Public, selectedSignet.Scheme == jess.SignetSchemePassword:
+			// nothing to protect
+		case selectedSignet.Protection != nil:
+			actions = append(actions, "Unprotect")
+		default:
+			actions = append(actions, "Protect")
+		}
+		actions = append(actions, "Back to list")
+
 		var selectedAction string
 		selectAction := &survey.Select{
-			Message: "Select action:",
-			Options: []string{
-				"Delete",
-				"Back to list",
-			},
+			Message:  "Select action:",
+			Options:  actions,
 			PageSize: 15,
 		}
 		err = survey.AskOne(selectAction, &selectedAction, nil)
@@ -97,6 +105,24 @@ func manageSignets() error {
 			if err != nil {
 				return err
 			}
+		case "Protect":
+			err = protectSignet(selectedSignet)
+			if err != nil {
+				return err
+			}
+			err = trustStore.StoreSignet(selectedSignet)
+			if err != nil {
+				return err
+			}
+		case "Unprotect":
+			err = selectedSignet.Unprotect(trustStore)
+			if err != nil {
+				return fmt.Errorf("failed to unprotect signet: %w", err)
+			}
+			err = trustStore.StoreSignet(selectedSignet)
+			if err != nil {
+				return err
+			}
 		case "Back to list":
 			continue
 		default:
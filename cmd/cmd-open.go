@@ -26,7 +26,7 @@ var (
 	openCmd = &cobra.Command{
 		Use:   "open <file>",
 		Short: "decrypt file",
-		Long:  "decrypt file with the given envelope. Use `-` to use stdin",
+		Long:  "decrypt file with the given envelope. Use `-` to use stdin. Files in the age format are detected automatically, but require `--no S`, as they do not authenticate their sender",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			registerPasswordCallbacks()
 
@@ -39,10 +39,14 @@ var (
 			filename := args[0]
 			outputFilename := openFlagOutput
 			if outputFilename == "" {
-				if !strings.HasSuffix(filename, ".letter") || len(filename) < 8 {
+				switch {
+				case strings.HasSuffix(filename, letterFileExtension) && len(filename) > len(letterFileExtension):
+					outputFilename = strings.TrimSuffix(filename, letterFileExtension)
+				case strings.HasSuffix(filename, ageFileExtension) && len(filename) > len(ageFileExtension):
+					outputFilename = strings.TrimSuffix(filename, ageFileExtension)
+				default:
 					return errors.New("cannot automatically derive output filename, please specify with --output")
 				}
-				outputFilename = strings.TrimSuffix(filename, ".letter")
 			}
 			// check input file
 			if filename != "-" {
@@ -87,21 +91,35 @@ var (
 				return err
 			}
 
-			// parse file
-			letter, err := jess.LetterFromFileFormat(container.New(data))
-			if err != nil {
-				return err
-			}
-
 			// Create default requirements if not set.
 			if requirements == nil {
 				requirements = jess.NewRequirements()
 			}
 
-			// decrypt (and verify)
-			plainText, err := letter.Open(requirements, trustStore)
-			if err != nil {
-				return err
+			var plainText []byte
+			if jess.IsAgeFormat(data) {
+				// age files are not signed, so their sender cannot be authenticated
+				if requirements.Has(jess.SenderAuthentication) {
+					return errors.New("age files do not authenticate their sender, use `--no S` to open them anyway")
+				}
+
+				// decrypt age file
+				plainText, err = jess.OpenAge(data, trustStore)
+				if err != nil {
+					return err
+				}
+			} else {
+				// parse file
+				letter, err := jess.LetterFromFileFormat(container.New(data))
+				if err != nil {
+					return err
+				}
+
+				// decrypt (and verify)
+				plainText, err = letter.Open(requirements, trustStore)
+				if err != nil {
+					return err
+				}
 			}
 
 			// open file for writing
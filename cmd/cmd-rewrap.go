@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/safing/jess"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	rootCmd.AddCommand(rewrapCmd)
+	rewrapCmd.Flags().StringVarP(&rewrapFlagOutput, "output", "o", "", "specify output file (`-` for stdout), defaults to replacing the input file")
+}
+
+var (
+	rewrapFlagOutput string
+	rewrapCmdHelp    = "usage: jess rewrap <file> to <recipient IDs>"
+
+	rewrapCmd = &cobra.Command{
+		Use:                   "rewrap <file> to <recipient IDs>",
+		Short:                 "replace the recipients of a file",
+		Long:                  "replace the recipients of a file with the given recipients, keeping the suite and secrets. If the file has a wrapped key, only the key seals are replaced and the encrypted data is kept as it is. Otherwise, the file is re-encrypted with a new, wrapped key, keeping padding and compression. Letter streams (file format version 2) are processed without loading them into memory. Signed files are not supported. Use `-` to use stdin",
+		DisableFlagsInUseLine: true,
+		PreRunE:               requireTrustStore,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			registerPasswordCallbacks()
+
+			// check args
+			if len(args) < 3 || args[1] != "to" {
+				return errors.New(rewrapCmdHelp)
+			}
+
+			// check filenames
+			filename := args[0]
+			outputFilename := rewrapFlagOutput
+			if outputFilename == "" {
+				if filename == stdInOutFilename {
+					return errors.New("cannot automatically derive output filename, please specify with --output")
+				}
+				outputFilename = filename
+			}
+			// check output file
+			if outputFilename != stdInOutFilename && outputFilename != filename {
+				_, err = os.Stat(outputFilename)
+				if err == nil {
+					confirmed, err := confirm("Output file already exists, overwrite?", true)
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						return nil
+					}
+				}
+			}
+
+			// open file
+			var input *os.File
+			if filename == stdInOutFilename {
+				input = os.Stdin
+			} else {
+				input, err = os.Open(filename)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = input.Close()
+				}()
+			}
+			reader := bufio.NewReader(input)
+
+			// Create default requirements if not set.
+			if requirements == nil {
+				requirements = jess.NewRequirements()
+			}
+
+			// get recipients
+			recipients := make([]*jess.Signet, 0, len(args)-2)
+			for _, id := range args[2:] {
+				recipients = append(recipients, &jess.Signet{
+					Version: 1,
+					ID:      id,
+				})
+			}
+
+			// Write to stdout directly, but to a temporary file otherwise, so
+			// that the input file is only replaced when rewrapping succeeded.
+			if outputFilename == stdInOutFilename {
+				return rewrapFile(os.Stdout, reader, recipients)
+			}
+			tmpFile, err := os.CreateTemp(filepath.Dir(outputFilename), "."+filepath.Base(outputFilename)+".*.tmp")
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = os.Remove(tmpFile.Name())
+			}()
+
+			// rewrap
+			err = rewrapFile(tmpFile, reader, recipients)
+			if err != nil {
+				_ = tmpFile.Close()
+				return err
+			}
+			err = tmpFile.Close()
+			if err != nil {
+				return err
+			}
+			return os.Rename(tmpFile.Name(), outputFilename)
+		},
+	}
+)
+
+func rewrapFile(w io.Writer, r *bufio.Reader, recipients []*jess.Signet) error {
+	// letter streams are rewrapped without loading them into memory
+	header, err := r.Peek(binary.MaxVarintLen64)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if jess.IsLetterStreamFormat(header) {
+		_, err = jess.RewrapStream(w, r, recipients, requirements, trustStore)
+		return err
+	}
+
+	// load and parse file
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	letter, err := jess.LetterFromFileFormat(container.New(data))
+	if err != nil {
+		return err
+	}
+
+	// rewrap
+	envelope, err := letter.Envelope(requirements)
+	if err != nil {
+		return err
+	}
+	newLetter, err := letter.Rewrap(envelope, recipients, trustStore)
+	if err != nil {
+		return err
+	}
+
+	// write in file format
+	c, err := newLetter.ToFileFormat()
+	if err != nil {
+		return err
+	}
+	return c.WriteAllTo(w)
+}
@@ -14,11 +14,19 @@ func init() {
 	rootCmd.AddCommand(signCmd)
 	signCmd.Flags().StringVarP(&closeFlagOutput, "output", "o", "", "specify output file (`-` for stdout")
 	signCmd.Flags().StringToStringVarP(&metaDataFlag, "metadata", "m", nil, "specify file metadata to sign")
+	signCmd.Flags().BoolVar(&signFlagSSHSig, "sshsig", false, "create an SSH signature for ssh-keygen -Y verify, instead of a jess signature")
+	signCmd.Flags().StringVar(&signFlagNamespace, "namespace", filesig.SSHSigDefaultNamespace, "specify the namespace of the SSH signature")
+	signCmd.Flags().BoolVar(&signFlagMinisign, "minisign", false, "create a minisign signature for minisign -V, instead of a jess signature")
+	signCmd.Flags().StringVar(&signFlagTrustedComment, "trusted-comment", "", "specify the trusted comment of the minisign signature, defaults to timestamp and file name")
 }
 
 var (
-	metaDataFlag map[string]string
-	signCmdHelp  = "usage: jess sign <file> with <envelope name>"
+	metaDataFlag           map[string]string
+	signFlagSSHSig         bool
+	signFlagNamespace      string
+	signFlagMinisign       bool
+	signFlagTrustedComment string
+	signCmdHelp            = "usage: jess sign <file> with <envelope name>"
 
 	signCmd = &cobra.Command{
 		Use:                   "sign <file> with <envelope name>",
@@ -42,12 +50,47 @@ var (
 
 			// check filenames
 			filename := args[0]
+			fileExtension := filesig.Extension
+			switch {
+			case signFlagSSHSig && signFlagMinisign:
+				return errors.New("cannot create SSH and minisign signatures at the same time")
+			case signFlagSSHSig:
+				fileExtension = filesig.SSHSigExtension
+			case signFlagMinisign:
+				fileExtension = filesig.MinisignExtension
+			}
 			outputFilename := closeFlagOutput
 			if outputFilename == "" {
-				if strings.HasSuffix(filename, filesig.Extension) {
+				if strings.HasSuffix(filename, fileExtension) {
 					return errors.New("cannot automatically derive output filename, please specify with --output")
 				}
-				outputFilename = filename + filesig.Extension
+				outputFilename = filename + fileExtension
+			}
+
+			// create SSH signature
+			if signFlagSSHSig {
+				if len(metaDataFlag) > 0 {
+					return errors.New("SSH signatures do not support metadata")
+				}
+				err := filesig.SignFileSSH(filename, outputFilename, signFlagNamespace, envelope, trustStore)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("signed %s with namespace %q, written to %s\n", filename, signFlagNamespace, outputFilename)
+				return nil
+			}
+
+			// create minisign signature
+			if signFlagMinisign {
+				if len(metaDataFlag) > 0 {
+					return errors.New("minisign signatures do not support metadata, use --trusted-comment instead")
+				}
+				err := filesig.SignFileMinisign(filename, outputFilename, signFlagTrustedComment, envelope, trustStore)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("signed %s with minisign, written to %s\n", filename, outputFilename)
+				return nil
 			}
 
 			fd, err := filesig.SignFile(filename, outputFilename, metaDataFlag, envelope, trustStore)
@@ -19,8 +19,11 @@ import (
 func init() {
 	rootCmd.AddCommand(verifyCmd)
 	verifyCmd.Flags().StringToStringVarP(&metaDataFlag, "metadata", "m", nil, "specify file metadata to verify (.sig only)")
+	verifyCmd.Flags().BoolVar(&verifyFlagIgnoreUnknown, "ignore-unknown-signers", false, "ignore signatures of signers that are not in the trust store, instead of failing (letters only)")
 }
 
+var verifyFlagIgnoreUnknown bool
+
 var verifyCmd = &cobra.Command{
 	Use:                   "verify <files and directories>",
 	Short:                 "verify signed files and files in directories",
@@ -82,8 +85,9 @@ var verifyCmd = &cobra.Command{
 						return nil
 					}
 
-					// Only verify if .sig or .letter.
+					// Only verify if .sig, .minisig or .letter.
 					if strings.HasSuffix(path, filesig.Extension) ||
+						strings.HasSuffix(path, filesig.MinisignExtension) ||
 						strings.HasSuffix(path, letterFileExtension) {
 						if err := verify(path, true); err != nil {
 							verificationFails++
@@ -135,6 +139,10 @@ func verify(filename string, bulkMode bool) error {
 		signedBy, err = verifyLetter(filename, bulkMode)
 	case strings.HasSuffix(filename, letterFileExtension):
 		signedBy, err = verifyLetter(filename, bulkMode)
+	case strings.HasSuffix(filename, filesig.MinisignExtension):
+		signame = filename
+		filename = strings.TrimSuffix(filename, filesig.MinisignExtension)
+		signedBy, err = verifyMinisig(filename, signame, bulkMode)
 	case strings.HasSuffix(filename, filesig.Extension):
 		filename = strings.TrimSuffix(filename, filesig.Extension)
 		fallthrough
@@ -207,28 +215,34 @@ func verifyLetter(filename string, silent bool) (signedBy []string, err error) {
 	}
 
 	// verify
-	err = letter.Verify(requirements, trustStore)
+	report, err := letter.VerifyDetailed(requirements, trustStore)
 	if err != nil {
 		return nil, err
 	}
 
 	// get signers
-	signedBy = make([]string, 0, len(letter.Signatures))
-	for _, seal := range letter.Signatures {
-		if signet, err := trustStore.GetSignet(seal.ID, true); err == nil {
-			signedBy = append(signedBy, fmt.Sprintf("%s (%s)", signet.Info.Name, seal.ID))
-		} else {
-			signedBy = append(signedBy, seal.ID)
+	var ignored []string
+	signedBy = make([]string, 0, len(report.Signatures))
+	for _, sig := range report.Signatures {
+		switch {
+		case !sig.Verified:
+			ignored = append(ignored, sig.ID)
+		case sig.Info != nil && sig.Info.Name != "":
+			signedBy = append(signedBy, fmt.Sprintf("%s (%s)", sig.Info.Name, sig.ID))
+		default:
+			signedBy = append(signedBy, sig.ID)
 		}
 	}
+	if len(ignored) > 0 && !verifyFlagIgnoreUnknown {
+		return nil, fmt.Errorf("signed by unknown signers %s, use --ignore-unknown-signers to ignore them", strings.Join(ignored, ", "))
+	}
 
 	// success
 	if !silent {
-		if err == nil {
-			fmt.Println("Verification: OK")
-			fmt.Printf("Signed By: %s\n", strings.Join(signedBy, ", "))
-		} else {
-			fmt.Printf("Verification FAILED: %s\n\n", err)
+		fmt.Println("Verification: OK")
+		fmt.Printf("Signed By: %s\n", strings.Join(signedBy, ", "))
+		if len(ignored) > 0 {
+			fmt.Printf("Ignored unknown signers: %s\n", strings.Join(ignored, ", "))
 		}
 	}
 
@@ -260,3 +274,27 @@ func verifySig(filename, signame string, silent bool) (signedBy []string, err er
 	}
 	return signedBy, nil
 }
+
+func verifyMinisig(filename, signame string, silent bool) (signedBy []string, err error) {
+	if len(metaDataFlag) > 0 {
+		return nil, errors.New("metadata flag only valid for verifying .sig files")
+	}
+
+	signet, trustedComment, err := filesig.VerifyFileMinisign(filename, signame, trustStore)
+	if err != nil {
+		return nil, err
+	}
+	if signet.Info != nil && signet.Info.Name != "" {
+		signedBy = []string{fmt.Sprintf("%s (%s)", signet.Info.Name, signet.ID)}
+	} else {
+		signedBy = []string{signet.ID}
+	}
+
+	if !silent {
+		fmt.Println("Verification: OK")
+		fmt.Printf("Signed By: %s\n", strings.Join(signedBy, ", "))
+		fmt.Printf("Trusted Comment: %s\n", trustedComment)
+	}
+
+	return signedBy, nil
+}
@@ -14,6 +14,10 @@ import (
 const (
 	stdInOutFilename    = "-"
 	letterFileExtension = ".letter"
+	ageFileExtension    = ".age"
+
+	formatLetter = "letter"
+	formatAge    = "age"
 
 	warnFileSize = 12000000 // 120MB
 )
@@ -0,0 +1,82 @@
+package jess
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Compression Algorithms.
+// Compressing data before encrypting it may leak information about the data through the size of the letter, if an attacker can influence parts of the data. Only enable compression if this is not the case.
+const (
+	// CompressionNone disables compression.
+	CompressionNone = "NONE"
+	// CompressionDeflate compresses data with DEFLATE (RFC 1951).
+	CompressionDeflate = "DEFLATE"
+)
+
+// DefaultMaxDecompressedSize is the default maximum size of decompressed data, see Session.SetMaxDecompressedSize.
+const DefaultMaxDecompressedSize = 67108864 // 64MB
+
+// ErrDecompressionLimit is returned when decompressed data exceeds the maximum allowed size.
+var ErrDecompressionLimit = errors.New("decompressed data exceeds size limit")
+
+// checkCompression checks the given compression algorithm and returns an empty string if compression is disabled.
+func checkCompression(compression string) (string, error) {
+	switch compression {
+	case "", CompressionNone:
+		return "", nil
+	case CompressionDeflate:
+		return compression, nil
+	default:
+		return "", fmt.Errorf("compression algorithm %s does not exist", compression)
+	}
+}
+
+// compress compresses the data with the given algorithm.
+func compress(compression string, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionDeflate:
+		buf := &bytes.Buffer{}
+		w, err := flate.NewWriter(buf, flate.DefaultCompression)
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Close()
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("compression algorithm %s does not exist", compression)
+	}
+}
+
+// decompress decompresses the data with the given algorithm. It fails if the decompressed data exceeds maxSize.
+func decompress(compression string, data []byte, maxSize int) ([]byte, error) {
+	switch compression {
+	case CompressionDeflate:
+		r := flate.NewReader(bytes.NewReader(data))
+		defer r.Close() //nolint:errcheck // Reading from memory.
+
+		// read one byte more than allowed in order to detect oversized data
+		decompressed, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		if len(decompressed) > maxSize {
+			return nil, ErrDecompressionLimit
+		}
+		return decompressed, nil
+
+	default:
+		return nil, fmt.Errorf("compression algorithm %s does not exist", compression)
+	}
+}
@@ -0,0 +1,89 @@
+package jess
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCompressedLetters(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Compression = CompressionDeflate
+	e.Padding = PaddingPadme
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compressible data
+	data := bytes.Repeat([]byte(testData1), 100)
+	letter, err := s.Close(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if letter.Compression != CompressionDeflate || len(letter.Data) >= len(testData1)*10 {
+		t.Fatalf("letter was not compressed: %d bytes with compression %q", len(letter.Data), letter.Compression)
+	}
+	opened, err := letter.Open(e.suite.Provides, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, opened) {
+		t.Fatal("original data mismatch")
+	}
+
+	// decompression limit
+	letter, err = s.Close(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2, err := letter.Envelope(e.suite.Provides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := e2.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2.SetMaxDecompressedSize(len(data) - 1)
+	_, err = s2.Open(letter)
+	if !errors.Is(err, ErrDecompressionLimit) {
+		t.Fatalf("letter exceeding decompression limit should fail, got: %v", err)
+	}
+
+	// compression is authenticated
+	letter, err = s.Close(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter.Compression = ""
+	_, err = letter.Open(e.suite.Provides, testTrustStore)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("letter with modified compression should fail with an integrity violation, got: %v", err)
+	}
+
+	// incompressible data is not compressed
+	data, err = RandomBytes(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter, err = s.Close(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if letter.Compression != "" {
+		t.Fatal("incompressible data should not be compressed")
+	}
+
+	// invalid algorithm
+	e.Compression = "ZIP"
+	_, err = e.Correspondence(testTrustStore)
+	if err == nil {
+		t.Fatal("invalid compression algorithm should fail")
+	}
+}
@@ -0,0 +1,248 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LetterFile provides random access to the data of a letter in file format version 2.
+// Only the chunks covering the requested range are read and decrypted. It is safe for concurrent use.
+// Call Close when done to burn the key material.
+type LetterFile struct {
+	// Letter holds the header and signatures of the letter, but no data.
+	Letter *Letter
+
+	session        *Session
+	reader         io.ReaderAt
+	streamKey      []byte
+	associatedData []byte
+
+	chunks []int64
+	size   int64
+	closed bool
+
+	lock sync.Mutex
+}
+
+// OpenLetterFile creates a session from the header of the letter file and opens it for random access in one step.
+// See Session.OpenLetterFile for details.
+func OpenLetterFile(r io.ReaderAt, requirements *Requirements, trustStore TrustStore) (*LetterFile, error) {
+	reader := &letterFileReader{r: r}
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file header: %w", err)
+	}
+
+	e, err := letter.Envelope(requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.openLetterFile(reader, letter)
+}
+
+// OpenLetterFile opens the letter file for random access.
+// The chunks are indexed and signatures are verified immediately, which requires reading, but not decrypting, the whole file if the letter is signed.
+// Letters that are only signed are refused, as the signature cannot be checked when reading single chunks. Use OpenStream for them instead.
+// The session must not be used for anything else while the letter file is in use.
+func (s *Session) OpenLetterFile(r io.ReaderAt) (*LetterFile, error) {
+	reader := &letterFileReader{r: r}
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file header: %w", err)
+	}
+
+	return s.openLetterFile(reader, letter)
+}
+
+func (s *Session) openLetterFile(reader *letterFileReader, letter *Letter) (*LetterFile, error) { //nolint:gocognit
+	if s.wire != nil {
+		return nil, errors.New("letter files are not supported in wire sessions")
+	}
+	if letter.Version != 1 {
+		return nil, fmt.Errorf("unsupported letter version: %d", letter.Version)
+	}
+	err := s.checkStreamingSupport()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.integratedCiphers) == 0 && len(s.macs) == 0 {
+		return nil, errors.New("letter files without authenticated chunks, such as only signed ones, do not support random access, use OpenStream instead")
+	}
+
+	// take announced signers from header
+	announcedSigners := letter.Signatures
+	letter.Signatures = nil
+
+	// key establishment
+	streamKey, err := s.setupOpeningStreamKey(letter)
+	if err != nil {
+		return nil, err
+	}
+
+	lf := &LetterFile{
+		Letter:         letter,
+		session:        s,
+		reader:         reader.r,
+		streamKey:      streamKey,
+		associatedData: letter.compileAssociatedData(),
+	}
+	opened := false
+	defer func() {
+		if !opened {
+			Burn(streamKey)
+		}
+	}()
+
+	// run managed signing hashers on header
+	associatedSigningData := letter.compileAssociatedSigningData(lf.associatedData)
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedHashers(s.managedSigningHashers, nil, associatedSigningData)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.resetManagedHashers(s.managedSigningHashers)
+	}
+
+	// index chunks
+	for index := uint64(0); ; index++ {
+		lf.chunks = append(lf.chunks, reader.offset)
+
+		var last bool
+		if s.managedSigningHashers != nil {
+			// read full chunk for signature verification
+			var data, mac []byte
+			data, mac, last, err = readStreamChunk(reader)
+			if err == nil {
+				err = s.feedManagedSigningHashersWithChunk(lf.associatedData, data, mac, index, last)
+			}
+		} else {
+			_, last, err = reader.skipChunk()
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("%w: file ended before last chunk", ErrIntegrityViolation)
+			}
+			return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+
+		if last {
+			break
+		}
+	}
+
+	// read signatures
+	signatures, err := readStreamSignatures(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file signatures: %w", err)
+	}
+	err = checkAnnouncedSignatures(announcedSigners, signatures)
+	if err != nil {
+		return nil, err
+	}
+	letter.Signatures = signatures
+
+	// Signature
+	if len(s.signers) > 0 {
+		err = s.verifyLetterSignatures(letter, nil, associatedSigningData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// get size by opening the last chunk
+	lastChunk, err := lf.openChunk(len(lf.chunks) - 1)
+	if err != nil {
+		return nil, err
+	}
+	lf.size = int64(len(lf.chunks)-1)*streamChunkSize + int64(len(lastChunk))
+
+	opened = true
+	return lf, nil
+}
+
+// Size returns the size of the original data.
+func (lf *LetterFile) Size() int64 {
+	return lf.size
+}
+
+// ReadAt implements io.ReaderAt. It reads and decrypts only the chunks covering the requested range.
+func (lf *LetterFile) ReadAt(p []byte, off int64) (n int, err error) {
+	switch {
+	case off < 0:
+		return 0, errors.New("negative offset")
+	case off >= lf.size:
+		return 0, io.EOF
+	}
+
+	lf.lock.Lock()
+	defer lf.lock.Unlock()
+
+	if lf.closed {
+		return 0, errors.New("letter file is closed")
+	}
+
+	for n < len(p) && off < lf.size {
+		data, err := lf.openChunk(int(off / streamChunkSize))
+		if err != nil {
+			return n, err
+		}
+
+		copied := copy(p[n:], data[off%streamChunkSize:])
+		n += copied
+		off += int64(copied)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close burns the key material of the letter file. It cannot be read afterwards.
+// The underlying reader is not closed.
+func (lf *LetterFile) Close() error {
+	lf.lock.Lock()
+	defer lf.lock.Unlock()
+
+	Burn(lf.streamKey)
+	lf.streamKey = nil
+	lf.closed = true
+	return nil
+}
+
+func (lf *LetterFile) openChunk(index int) ([]byte, error) {
+	reader := &letterFileReader{
+		r:      lf.reader,
+		offset: lf.chunks[index],
+	}
+	data, mac, last, err := readStreamChunk(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+	}
+
+	// check if the chunk is where it is supposed to be
+	if last != (index == len(lf.chunks)-1) {
+		return nil, fmt.Errorf("%w: chunk %d has an invalid last flag", ErrIntegrityViolation, index)
+	}
+
+	data, err = lf.session.openStreamChunk(lf.Letter, lf.streamKey, lf.associatedData, data, mac, uint64(index), last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chunk %d: %w", index, err)
+	}
+
+	// all chunks but the last must be full
+	if (!last && len(data) != streamChunkSize) || len(data) > streamChunkSize {
+		return nil, fmt.Errorf("%w: chunk %d has an invalid size", ErrIntegrityViolation, index)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,213 @@
+package jess
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLetterFile(t *testing.T) {
+	t.Parallel()
+
+	for _, suite := range Suites() {
+		// password derivation is slow and does not affect letter files
+		if suiteUsesPassDerivation(suite) && !runComprehensiveTestsActive {
+			continue
+		}
+		// payload sealers do not support streaming
+		if suiteSealsPayload(suite) {
+			continue
+		}
+		// only signed letter files do not support random access
+		if !suiteAuthenticatesData(suite) {
+			continue
+		}
+		testLetterFile(t, suite)
+	}
+}
+
+func testLetterFile(t *testing.T, suite *Suite) { //nolint:thelper
+	t.Logf("testing letter file with %s", suite.ID)
+
+	e, err := setupEnvelopeAndTrustStore(t, suite)
+	if err != nil {
+		tErrorf(t, "%s failed: %s", suite.ID, err)
+		return
+	}
+	if e == nil {
+		return
+	}
+
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		tErrorf(t, "%s failed to init session: %s", suite.ID, err)
+		return
+	}
+
+	data, err := RandomBytes(3*streamChunkSize + len(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := &bytes.Buffer{}
+	_, err = s.CloseStream(file, bytes.NewReader(data))
+	if err != nil {
+		tErrorf(t, "%s failed to close: %s", suite.ID, err)
+		return
+	}
+
+	lf, err := OpenLetterFile(bytes.NewReader(file.Bytes()), e.suite.Provides, testTrustStore)
+	if err != nil {
+		tErrorf(t, "%s failed to open letter file: %s", suite.ID, err)
+		return
+	}
+	if lf.Size() != int64(len(data)) {
+		tErrorf(t, "%s size mismatch: got %d, expected %d", suite.ID, lf.Size(), len(data))
+		return
+	}
+
+	// describe
+	if !IsLetterStreamFormat(file.Bytes()) {
+		tErrorf(t, "%s letter file should be detected as stream format", suite.ID)
+		return
+	}
+	desc, err := DescribeLetterFile(bytes.NewReader(file.Bytes()), testTrustStore)
+	if err != nil {
+		tErrorf(t, "%s failed to describe letter file: %s", suite.ID, err)
+		return
+	}
+	if desc.SuiteID != suite.ID ||
+		len(desc.Keys) != len(lf.Letter.Keys) ||
+		len(desc.Signatures) != len(lf.Letter.Signatures) ||
+		desc.DataSize < len(data) {
+		tErrorf(t, "%s unexpected letter file description: %+v", suite.ID, desc)
+		return
+	}
+
+	// read ranges within and across chunks
+	for _, r := range [][2]int{
+		{0, 10},
+		{streamChunkSize - 5, 10},
+		{2*streamChunkSize + 10, streamChunkSize},
+		{len(data) - 20, 20},
+		{0, len(data)},
+	} {
+		buf := make([]byte, r[1])
+		n, err := lf.ReadAt(buf, int64(r[0]))
+		if err != nil {
+			tErrorf(t, "%s failed to read %d bytes at %d: %s", suite.ID, r[1], r[0], err)
+			return
+		}
+		if n != r[1] || !bytes.Equal(buf, data[r[0]:r[0]+r[1]]) {
+			tErrorf(t, "%s data mismatch when reading %d bytes at %d", suite.ID, r[1], r[0])
+			return
+		}
+	}
+
+	// reading beyond the end
+	buf := make([]byte, 30)
+	n, err := lf.ReadAt(buf, int64(len(data)-10))
+	if n != 10 || !errors.Is(err, io.EOF) {
+		tErrorf(t, "%s unexpected result when reading beyond the end: %d, %v", suite.ID, n, err)
+		return
+	}
+
+	// reading after closing
+	err = lf.Close()
+	if err != nil {
+		tErrorf(t, "%s failed to close letter file: %s", suite.ID, err)
+		return
+	}
+	_, err = lf.ReadAt(buf, 0)
+	if err == nil {
+		tErrorf(t, "%s reading a closed letter file should fail", suite.ID)
+		return
+	}
+}
+
+func TestSignOnlyLetterFile(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteSignFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := &bytes.Buffer{}
+	_, err = s.CloseStream(file, bytes.NewReader([]byte(testData1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desc, err := DescribeLetterFile(bytes.NewReader(file.Bytes()), testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Authenticated {
+		t.Fatal("sign-only letter file should not be described as authenticated")
+	}
+	if len(desc.Signatures) == 0 {
+		t.Fatal("sign-only letter file should be described with signatures")
+	}
+
+	// Random access cannot verify the signature on every read.
+	_, err = OpenLetterFile(bytes.NewReader(file.Bytes()), e.suite.Provides, testTrustStore)
+	if err == nil {
+		t.Fatal("opening a sign-only letter file for random access should fail")
+	}
+	_, err = OpenStream(io.Discard, bytes.NewReader(file.Bytes()), e.suite.Provides, testTrustStore)
+	if err != nil {
+		t.Fatalf("sign-only letter file should be opened as stream: %s", err)
+	}
+}
+
+func TestLetterFileTampering(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteKeyV1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := RandomBytes(3 * streamChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := &bytes.Buffer{}
+	_, err = s.CloseStream(file, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modify second chunk
+	modified := file.Bytes()
+	modified[len(modified)-2*streamChunkSize] ^= 0x01
+
+	lf, err := OpenLetterFile(bytes.NewReader(modified), e.suite.Provides, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = lf.Close()
+	}()
+
+	// first chunk is still readable
+	buf := make([]byte, 100)
+	_, err = lf.ReadAt(buf, 0)
+	if err != nil {
+		t.Fatalf("failed to read unmodified chunk: %s", err)
+	}
+
+	// second chunk must fail
+	_, err = lf.ReadAt(buf, streamChunkSize+100)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("modified chunk should fail with an integrity violation, got: %v", err)
+	}
+}
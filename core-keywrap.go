@@ -0,0 +1,91 @@
+package jess
+
+import (
+	"crypto/subtle"
+	"fmt"
+)
+
+// Size limits of wrapped data keys.
+const (
+	minWrappedKeySize = 32 // 256 bits
+	maxWrappedKeySize = 64 // 512 bits
+)
+
+// initClosingKeyDerivation initializes the key derivation for closing the letter with the given key material.
+// If key wrapping is enabled, a new random data key is wrapped with the key material and used for the key derivation instead.
+func (s *Session) initClosingKeyDerivation(letter *Letter, keyMaterial [][]byte) error {
+	if !s.wrapKey {
+		err := s.kdf.InitKeyDerivation(letter.Nonce, keyMaterial...)
+		if err != nil {
+			return fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+		}
+		return nil
+	}
+
+	// create data key
+	dataKey, err := RandomBytes(min(max(s.DefaultSymmetricKeySize, minWrappedKeySize), maxWrappedKeySize))
+	if err != nil {
+		return fmt.Errorf("failed to get data key: %w", err)
+	}
+	defer Burn(dataKey)
+
+	// wrap data key
+	letter.WrappedKey, err = s.wrapDataKey(letter, dataKey, keyMaterial)
+	if err != nil {
+		return err
+	}
+
+	err = s.kdf.InitKeyDerivation(letter.Nonce, dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+	}
+	return nil
+}
+
+// initOpeningKeyDerivation initializes the key derivation for opening the letter with the given key material.
+// If the letter has a wrapped key, it is unwrapped with the key material and used for the key derivation instead.
+func (s *Session) initOpeningKeyDerivation(letter *Letter, keyMaterial [][]byte) error {
+	if len(letter.WrappedKey) == 0 {
+		err := s.kdf.InitKeyDerivation(letter.Nonce, keyMaterial...)
+		if err != nil {
+			return fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+		}
+		return nil
+	}
+
+	// unwrap data key
+	dataKey, err := s.wrapDataKey(letter, letter.WrappedKey, keyMaterial)
+	if err != nil {
+		return err
+	}
+	defer Burn(dataKey)
+
+	err = s.kdf.InitKeyDerivation(letter.Nonce, dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+	}
+	return nil
+}
+
+// wrapDataKey wraps or unwraps the data key by XORing it with a key stream derived from the nonce and the key material of the letter.
+// The key stream only changes with the key material, but as the nonce is bound to the data, it only ever wraps the same data key.
+// The data key itself is not authenticated, but all keys derived from a wrong data key fail to authenticate the data.
+func (s *Session) wrapDataKey(letter *Letter, dataKey []byte, keyMaterial [][]byte) ([]byte, error) {
+	if len(dataKey) < minWrappedKeySize || len(dataKey) > maxWrappedKeySize {
+		return nil, fmt.Errorf("wrapped key must be between %d and %d bytes", minWrappedKeySize, maxWrappedKeySize)
+	}
+
+	err := s.kdf.InitKeyDerivation(letter.Nonce, keyMaterial...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+	}
+	keyStream, err := s.kdf.DeriveKey(len(dataKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key stream for wrapping: %w", err)
+	}
+	defer Burn(keyStream)
+
+	wrapped := make([]byte, len(dataKey))
+	subtle.XORBytes(wrapped, dataKey, keyStream)
+	return wrapped, nil
+}
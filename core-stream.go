@@ -0,0 +1,503 @@
+package jess
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+// CloseStream encrypts (and possibly signs) the data read from r in chunks and writes the resulting letter stream to w.
+// The returned letter holds the header and signatures of the stream, but no data.
+func (s *Session) CloseStream(w io.Writer, r io.Reader) (*Letter, error) { //nolint:gocognit
+	if s.wire != nil {
+		return nil, errors.New("streaming is not supported in wire sessions")
+	}
+	err := s.checkStreamingSupport()
+	if err != nil {
+		return nil, err
+	}
+	if s.padding != nil {
+		return nil, errors.New("padding is not supported for streams")
+	}
+	if s.compression != "" {
+		return nil, errors.New("compression is not supported for streams")
+	}
+
+	letter := &Letter{
+		Version: s.envelope.Version,
+		SuiteID: s.envelope.SuiteID,
+	}
+	letter.SignedAt = s.signedAt()
+
+	// ==============
+	// key management
+	// ==============
+
+	// create nonce
+	nonce, err := RandomBytes(s.NonceSize())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	letter.Nonce = nonce
+
+	var streamKey []byte
+	if s.kdf != nil {
+		// if we require a key
+
+		// key establishment
+		keyMaterial, err := s.setupClosingKeyMaterial(letter)
+		if err != nil {
+			return nil, err
+		}
+
+		// init KDF
+		err = s.initClosingKeyDerivation(letter, keyMaterial)
+		if err != nil {
+			return nil, err
+		}
+
+		// derive stream key, from which all chunk keys are derived
+		streamKey, err = s.deriveStreamKey()
+		if err != nil {
+			return nil, err
+		}
+		defer Burn(streamKey)
+
+	} else if len(s.ciphers) > 0 || len(s.integratedCiphers) > 0 || len(s.macs) > 0 {
+		// check if there is really nothing to do with a key
+		return nil, errors.New("missing a kdf tool")
+	}
+
+	// announce signers in header
+	for _, tool := range s.signers {
+		_ = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+			letter.Signatures = append(letter.Signatures, &Seal{
+				Scheme: tool.Info().Name,
+				ID:     signet.ID,
+			})
+			return nil
+		})
+	}
+
+	// write header
+	err = writeStreamHeader(w, letter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write stream header: %w", err)
+	}
+	letter.Signatures = nil
+
+	// build associated data
+	associatedData := letter.compileAssociatedData()
+	associatedSigningData := letter.compileAssociatedSigningData(associatedData)
+
+	// run managed signing hashers on header
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedHashers(s.managedSigningHashers, nil, associatedSigningData)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.resetManagedHashers(s.managedSigningHashers)
+	}
+
+	// ==========
+	// encryption
+	// ==========
+
+	reader := bufio.NewReader(r)
+	for index := uint64(0); ; index++ {
+		data, last, err := readStreamPlaintextChunk(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+
+		data, mac, err := s.closeStreamChunk(letter, streamKey, associatedData, data, index, last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to close chunk %d: %w", index, err)
+		}
+
+		err = writeStreamChunk(w, data, mac, last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write chunk %d: %w", index, err)
+		}
+
+		if last {
+			break
+		}
+	}
+
+	// Signature
+	if len(s.signers) > 0 {
+		err = s.signLetter(letter, nil, associatedSigningData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// write signatures
+	err = writeStreamSignatures(w, letter.Signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write stream signatures: %w", err)
+	}
+
+	return letter, nil
+}
+
+func (s *Session) closeStreamChunk(letter *Letter, streamKey, associatedData, data []byte, index uint64, last bool) (chunkData, mac []byte, err error) {
+	associatedChunkData := compileAssociatedChunkData(associatedData, index, last)
+
+	if s.kdf != nil {
+		// init chunk KDF
+		err = s.kdf.InitKeyDerivation(compileStreamChunkNonce(letter.Nonce, index), streamKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+		}
+
+		// setup tools
+		err = s.setup()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer s.reset() //nolint:errcheck // TODO: handle error? Currently there should be none.
+
+		// Ciphers
+		for _, tool := range s.ciphers {
+			data, err = tool.Encrypt(data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to encrypt with %s: %w", tool.Info().Name, err)
+			}
+		}
+
+		// Integrated Ciphers / AEAD
+		for _, tool := range s.integratedCiphers {
+			data, err = tool.AuthenticatedEncrypt(data, associatedChunkData)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to auth-encrypt with %s: %w", tool.Info().Name, err)
+			}
+		}
+
+		// MAC
+		if len(s.macs) > 0 {
+			mac, err = s.calculateStreamChunkMAC(data, associatedChunkData)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// run managed signing hashers
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedSigningHashersWithChunk(associatedData, data, mac, index, last)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return data, mac, nil
+}
+
+// OpenStream reads a letter stream from r, decrypts (and possibly verifies) it and writes the original data to w.
+// Chunks are written to w as soon as they are authenticated, but signatures can only be verified at the end of the stream.
+// If an error is returned, all data written to w must be discarded.
+func (s *Session) OpenStream(w io.Writer, r io.Reader) (*Letter, error) {
+	reader := bufio.NewReader(r)
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+
+	err = s.openStream(w, reader, letter)
+	if err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+// OpenStream creates a session from the header of the letter stream read from r and opens the stream in one step.
+// See Session.OpenStream for details.
+func OpenStream(w io.Writer, r io.Reader, requirements *Requirements, trustStore TrustStore) (*Letter, error) {
+	reader := bufio.NewReader(r)
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+
+	e, err := letter.Envelope(requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.openStream(w, reader, letter)
+	if err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+func (s *Session) openStream(w io.Writer, reader *bufio.Reader, letter *Letter) error { //nolint:gocognit
+	if s.wire != nil {
+		return errors.New("streaming is not supported in wire sessions")
+	}
+	if letter.Version != 1 {
+		return fmt.Errorf("unsupported letter version: %d", letter.Version)
+	}
+	err := s.checkStreamingSupport()
+	if err != nil {
+		return err
+	}
+
+	// take announced signers from header
+	announcedSigners := letter.Signatures
+	letter.Signatures = nil
+
+	// key establishment
+	streamKey, err := s.setupOpeningStreamKey(letter)
+	if err != nil {
+		return err
+	}
+	defer Burn(streamKey)
+
+	// build associated data
+	associatedData := letter.compileAssociatedData()
+	associatedSigningData := letter.compileAssociatedSigningData(associatedData)
+
+	// run managed signing hashers on header
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedHashers(s.managedSigningHashers, nil, associatedSigningData)
+		if err != nil {
+			return err
+		}
+
+		defer s.resetManagedHashers(s.managedSigningHashers)
+	}
+
+	// ==========
+	// decryption
+	// ==========
+
+	for index := uint64(0); ; index++ {
+		data, mac, last, err := readStreamChunk(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return fmt.Errorf("%w: stream ended before last chunk", ErrIntegrityViolation)
+			}
+			return fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+
+		// run managed signing hashers
+		if s.managedSigningHashers != nil {
+			err = s.feedManagedSigningHashersWithChunk(associatedData, data, mac, index, last)
+			if err != nil {
+				return err
+			}
+		}
+
+		data, err = s.openStreamChunk(letter, streamKey, associatedData, data, mac, index, last)
+		if err != nil {
+			return fmt.Errorf("failed to open chunk %d: %w", index, err)
+		}
+
+		_, err = w.Write(data)
+		if err != nil {
+			return fmt.Errorf("failed to write chunk %d: %w", index, err)
+		}
+
+		if last {
+			break
+		}
+	}
+
+	// read signatures
+	signatures, err := readStreamSignatures(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read stream signatures: %w", err)
+	}
+	err = checkAnnouncedSignatures(announcedSigners, signatures)
+	if err != nil {
+		return err
+	}
+	for _, seal := range signatures {
+		if seal.SignedAt != 0 {
+			return errors.New("counter-signatures are not supported in streams")
+		}
+	}
+	letter.Signatures = signatures
+
+	// Signature
+	if len(s.signers) > 0 {
+		err = s.verifyLetterSignatures(letter, nil, associatedSigningData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkAnnouncedSignatures(announced, signatures []*Seal) error {
+	if len(signatures) != len(announced) {
+		return errors.New("mismatch regarding announced and available signatures")
+	}
+	for i, seal := range signatures {
+		if seal.Scheme != announced[i].Scheme || seal.ID != announced[i].ID {
+			return errors.New("mismatch regarding announced and available signatures")
+		}
+	}
+	return nil
+}
+
+func (s *Session) setupOpeningStreamKey(letter *Letter) (streamKey []byte, err error) {
+	if s.kdf == nil {
+		// check if there is really nothing to do with a key
+		if len(s.ciphers) > 0 || len(s.integratedCiphers) > 0 || len(s.macs) > 0 {
+			return nil, errors.New("missing a kdf tool")
+		}
+		return nil, nil
+	}
+
+	// key establishment
+	keyMaterial, err := s.setupOpeningKeyMaterial(letter)
+	if err != nil {
+		return nil, err
+	}
+
+	// init KDF
+	err = s.initOpeningKeyDerivation(letter, keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+
+	// derive stream key, from which all chunk keys are derived
+	return s.deriveStreamKey()
+}
+
+func (s *Session) openStreamChunk(letter *Letter, streamKey, associatedData, data, mac []byte, index uint64, last bool) ([]byte, error) {
+	associatedChunkData := compileAssociatedChunkData(associatedData, index, last)
+
+	// end early if we are only verifying sigs
+	if s.kdf == nil {
+		if len(mac) > 0 {
+			return nil, fmt.Errorf("%w: unexpected MAC", ErrIntegrityViolation)
+		}
+		return data, nil
+	}
+
+	// init chunk KDF
+	err := s.kdf.InitKeyDerivation(compileStreamChunkNonce(letter.Nonce, index), streamKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+	}
+
+	// setup tools
+	err = s.setup()
+	if err != nil {
+		return nil, err
+	}
+	defer s.reset() //nolint:errcheck // TODO: handle error? Currently there should be none.
+
+	// MAC
+	if len(s.macs) > 0 {
+		chunkMac, err := s.calculateStreamChunkMAC(data, associatedChunkData)
+		if err != nil {
+			return nil, err
+		}
+		if subtle.ConstantTimeCompare(mac, chunkMac) != 1 {
+			return nil, fmt.Errorf("%w: MAC verification failed", ErrIntegrityViolation)
+		}
+	}
+
+	// Integrated Ciphers / AEAD (in reversed order)
+	for i := len(s.integratedCiphers) - 1; i >= 0; i-- {
+		data, err = s.integratedCiphers[i].AuthenticatedDecrypt(data, associatedChunkData)
+		if err != nil {
+			return nil, fmt.Errorf("%w: [%s] %w", ErrIntegrityViolation, s.integratedCiphers[i].Info().Name, err)
+		}
+	}
+
+	// Ciphers (in reversed order)
+	for i := len(s.ciphers) - 1; i >= 0; i-- {
+		data, err = s.ciphers[i].Decrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: decryption failed: [%s] %w", ErrIntegrityViolation, s.ciphers[i].Info().Name, err)
+		}
+	}
+
+	return data, nil
+}
+
+func (s *Session) calculateStreamChunkMAC(data, associatedChunkData []byte) ([]byte, error) {
+	// run managed mac hashers
+	if s.managedMACHashers != nil {
+		err := s.feedManagedHashers(s.managedMACHashers, data, associatedChunkData)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.resetManagedHashers(s.managedMACHashers)
+	}
+
+	// run MAC tools
+	allMacs := container.New()
+	for _, tool := range s.macs {
+		mac, err := tool.MAC(data, associatedChunkData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate MAC with %s: %w", tool.Info().Name, err)
+		}
+		allMacs.Append(mac)
+	}
+	return allMacs.CompileData(), nil
+}
+
+func (s *Session) feedManagedSigningHashersWithChunk(associatedData, data, mac []byte, index uint64, last bool) error {
+	return s.feedManagedHashers(
+		s.managedSigningHashers,
+		data,
+		compileAssociatedChunkSigningData(compileAssociatedChunkData(associatedData, index, last), mac),
+	)
+}
+
+func (s *Session) deriveStreamKey() ([]byte, error) {
+	if s.DefaultSymmetricKeySize == 0 {
+		return nil, errors.New("missing default key size")
+	}
+
+	streamKey, err := s.kdf.DeriveKey(s.DefaultSymmetricKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive stream key: %w", err)
+	}
+	return streamKey, nil
+}
+
+// checkStreamingSupport checks if all tools of the session support streaming.
+func (s *Session) checkStreamingSupport() error {
+	if s.payloadSealer != nil {
+		return fmt.Errorf("tool %s does not support streaming", s.payloadSealer.Info().Name)
+	}
+
+	for _, toolList := range [][]tools.ToolLogic{s.ciphers, s.integratedCiphers, s.macs} {
+		for _, tool := range toolList {
+			if !tool.Info().HasOption(tools.OptionStreaming) {
+				return fmt.Errorf("tool %s does not support streaming", tool.Info().Name)
+			}
+		}
+	}
+
+	// signers must only work on the managed hash sum
+	for _, tool := range s.signers {
+		if !tool.Info().HasOption(tools.OptionNeedsManagedHasher) {
+			return fmt.Errorf("tool %s does not support streaming", tool.Info().Name)
+		}
+	}
+
+	return nil
+}
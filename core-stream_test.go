@@ -0,0 +1,207 @@
+package jess
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/safing/jess/tools"
+)
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	for _, suite := range Suites() {
+		// password derivation is slow and does not affect streaming
+		if suiteUsesPassDerivation(suite) && !runComprehensiveTestsActive {
+			continue
+		}
+		// payload sealers do not support streaming
+		if suiteSealsPayload(suite) {
+			continue
+		}
+		testStream(t, suite)
+	}
+}
+
+func suiteUsesPassDerivation(suite *Suite) bool {
+	for _, toolID := range suite.Tools {
+		tool, err := tools.Get(strings.Split(toolID, "(")[0])
+		if err == nil && tool.Info.Purpose == tools.PurposePassDerivation {
+			return true
+		}
+	}
+	return false
+}
+
+func suiteSealsPayload(suite *Suite) bool {
+	for _, toolID := range suite.Tools {
+		tool, err := tools.Get(strings.Split(toolID, "(")[0])
+		if err == nil && tool.Info.HasOption(tools.OptionSealsPayload) {
+			return true
+		}
+	}
+	return false
+}
+
+func testStream(t *testing.T, suite *Suite) { //nolint:thelper
+	t.Logf("testing stream with %s", suite.ID)
+
+	e, err := setupEnvelopeAndTrustStore(t, suite)
+	if err != nil {
+		tErrorf(t, "%s failed: %s", suite.ID, err)
+		return
+	}
+	if e == nil {
+		return
+	}
+
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		tErrorf(t, "%s failed to init session: %s", suite.ID, err)
+		return
+	}
+
+	for _, size := range []int{0, 2*streamChunkSize + len(testData1)} {
+		data, err := RandomBytes(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// close
+		stream := &bytes.Buffer{}
+		_, err = s.CloseStream(stream, bytes.NewReader(data))
+		if err != nil {
+			tErrorf(t, "%s failed to close stream of size %d: %s", suite.ID, size, err)
+			return
+		}
+		closedStream := stream.Bytes()
+
+		// open
+		opened := &bytes.Buffer{}
+		_, err = OpenStream(opened, bytes.NewReader(closedStream), e.suite.Provides, testTrustStore)
+		if err != nil {
+			tErrorf(t, "%s failed to open stream of size %d: %s", suite.ID, size, err)
+			return
+		}
+		if !bytes.Equal(opened.Bytes(), data) {
+			tErrorf(t, "%s original data mismatch in stream of size %d", suite.ID, size)
+			return
+		}
+
+		// truncated stream must fail
+		_, err = OpenStream(&bytes.Buffer{}, bytes.NewReader(closedStream[:len(closedStream)-20]), e.suite.Provides, testTrustStore)
+		if err == nil {
+			tErrorf(t, "%s truncated stream of size %d was opened", suite.ID, size)
+			return
+		}
+
+		// modified stream must fail
+		if size > 0 {
+			modified := make([]byte, len(closedStream))
+			copy(modified, closedStream)
+			modified[len(modified)-size/2-100] ^= 0x01
+			_, err = OpenStream(&bytes.Buffer{}, bytes.NewReader(modified), e.suite.Provides, testTrustStore)
+			if err == nil {
+				tErrorf(t, "%s modified stream of size %d was opened", suite.ID, size)
+				return
+			}
+		}
+	}
+}
+
+func TestStreamChunkReorder(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteCompleteV1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := RandomBytes(3 * streamChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := &bytes.Buffer{}
+	_, err = s.CloseStream(stream, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// parse stream
+	reader := bufio.NewReader(stream)
+	header, err := readStreamHeader(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks [][]byte
+	var macs [][]byte
+	for {
+		chunk, mac, last, err := readStreamChunk(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+		macs = append(macs, mac)
+		if last {
+			break
+		}
+	}
+
+	// rebuild with first two chunks swapped
+	reordered := &bytes.Buffer{}
+	err = writeStreamHeader(reordered, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks[0], chunks[1] = chunks[1], chunks[0]
+	macs[0], macs[1] = macs[1], macs[0]
+	for i := range chunks {
+		err = writeStreamChunk(reordered, chunks[i], macs[i], i == len(chunks)-1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = OpenStream(&bytes.Buffer{}, reordered, e.suite.Provides, testTrustStore)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("reordered stream should fail with an integrity violation, got: %s", err)
+	}
+}
+
+func TestStreamTrailingData(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteCompleteV1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := &bytes.Buffer{}
+	_, err = s.CloseStream(stream, strings.NewReader(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// append garbage after the signatures
+	stream.WriteString("garbage")
+
+	_, err = OpenStream(&bytes.Buffer{}, bytes.NewReader(stream.Bytes()), e.suite.Provides, testTrustStore)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("stream with trailing data should fail with an integrity violation, got: %v", err)
+	}
+	_, err = OpenLetterFile(bytes.NewReader(stream.Bytes()), e.suite.Provides, testTrustStore)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("letter file with trailing data should fail with an integrity violation, got: %v", err)
+	}
+}
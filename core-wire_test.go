@@ -14,18 +14,61 @@ func TestWire(t *testing.T) {
 	wireReKeyAfterMsgs = 100
 
 	// current suites recommendation
-	testWireCorrespondence(t, getSuite(t, SuiteWire), testData1)
-	testWireCorrespondence(t, getSuite(t, SuiteWire), testData2)
+	testWireCorrespondence(t, getSuite(t, SuiteWire), testData1, false, "")
+	testWireCorrespondence(t, getSuite(t, SuiteWire), testData2, false, "")
+
+	// concealed wire format
+	testWireCorrespondence(t, getSuite(t, SuiteWire), testData1, true, "")
+
+	// padding
+	testWireCorrespondence(t, getSuite(t, SuiteWire), testData1, false, "BLOCK(64)")
+
+	// hybrid post-quantum suite
+	testWireCorrespondence(t, getSuite(t, SuiteWirePQV1), testData1, false, "")
+	testWireCorrespondence(t, getSuite(t, SuiteWirePQV1), testData2, false, "")
+
+	// high-security suite
+	testWireCorrespondence(t, getSuite(t, SuiteWireHSV1), testData1, false, "")
+	testWireCorrespondence(t, getSuite(t, SuiteWireHSV1), testData2, false, "")
 
 	// older suites
-	// testWireCorrespondence(t, getSuite(t, SuiteWireV1), testData1)
-	// testWireCorrespondence(t, getSuite(t, SuiteWireV1), testData2)
+	// testWireCorrespondence(t, getSuite(t, SuiteWireV1), testData1, false, "")
+	// testWireCorrespondence(t, getSuite(t, SuiteWireV1), testData2, false, "")
 }
 
-func testWireCorrespondence(t *testing.T, suite *Suite, testData string) {
-	t.Helper()
+func TestWireForgedLetter(t *testing.T) {
+	t.Parallel()
 
 	wtr := &wireTestRange{t: t}
+	wtr.init(getSuite(t, SuiteWire), testData1)
+	wtr.clientSend()
+	wtr.serverRecv()
+
+	// forge a padded letter in the concealed wire format
+	letter, err := wtr.client.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter.Data[0] ^= 0xFF
+	letter.wireFormat = wireFormatConcealed
+	letter.Padded = true
+
+	_, err = wtr.server.Open(letter)
+	if err == nil {
+		t.Fatal("forged letter should be rejected")
+	}
+	if wtr.server.wire.concealed {
+		t.Fatal("forged letter should not switch the session to the concealed wire format")
+	}
+	if wtr.server.padding != nil {
+		t.Fatal("forged letter should not switch the padding policy of the session")
+	}
+}
+
+func testWireCorrespondence(t *testing.T, suite *Suite, testData string, conceal bool, padding string) {
+	t.Helper()
+
+	wtr := &wireTestRange{t: t, conceal: conceal, padding: padding}
 	wtr.init(suite, testData)
 	fmt.Printf("\n\nsimulating %v\n", suite.ID)
 	fmt.Println("two dots are one packet send+recv:")
@@ -97,6 +140,8 @@ type wireTestRange struct {
 	t        *testing.T
 	suite    *Suite
 	testData string
+	conceal  bool
+	padding  string
 
 	client *Session
 	server *Session
@@ -122,11 +167,15 @@ func (wtr *wireTestRange) init(suite *Suite, testData string) (detectedInvalid b
 	if e == nil {
 		return true
 	}
+	e.Padding = wtr.padding
 
 	wtr.client, err = e.WireCorrespondence(testTrustStore)
 	if err != nil {
 		wtr.t.Fatalf("%s failed to init client session: %s", wtr.suite.ID, err)
 	}
+	if wtr.conceal {
+		wtr.client.ConcealWire()
+	}
 
 	// setup and reset
 	wtr.testData = testData
@@ -152,6 +201,9 @@ func (wtr *wireTestRange) clientSend() {
 	if err != nil {
 		wtr.t.Fatalf("%s failed to serialize to wire: %s", wtr.suite.ID, err)
 	}
+	if wtr.conceal && wireData.Peek(1)[0] == wireFormatSimple {
+		wtr.t.Fatalf("%s wire data is not concealed", wtr.suite.ID)
+	}
 
 	select {
 	case wtr.clientToServer <- wireData:
@@ -184,6 +236,9 @@ func (wtr *wireTestRange) serverRecv() {
 		if err != nil {
 			wtr.t.Fatalf("%s failed to open: %s", wtr.suite.ID, err)
 		}
+		if wtr.padding != "" && !letter.Padded {
+			wtr.t.Fatalf("%s letter is not padded", wtr.suite.ID)
+		}
 		wtr.bytesTransferred += len(origData)
 
 		if string(origData) != wtr.testData {
@@ -206,6 +261,9 @@ func (wtr *wireTestRange) serverSend() {
 	if err != nil {
 		wtr.t.Fatalf("%s failed to serialize to wire: %s", wtr.suite.ID, err)
 	}
+	if wtr.conceal && wireData.Peek(1)[0] == wireFormatSimple {
+		wtr.t.Fatalf("%s wire data is not concealed", wtr.suite.ID)
+	}
 
 	select {
 	case wtr.serverToClient <- wireData:
@@ -231,6 +289,9 @@ func (wtr *wireTestRange) clientRecv() {
 		if err != nil {
 			wtr.t.Fatalf("%s failed to open: %s", wtr.suite.ID, err)
 		}
+		if wtr.padding != "" && !letter.Padded {
+			wtr.t.Fatalf("%s letter is not padded", wtr.suite.ID)
+		}
 		wtr.bytesTransferred += len(origData)
 
 		if string(origData) != wtr.testData {
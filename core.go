@@ -5,23 +5,53 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/safing/jess/tools"
 	"github.com/safing/structures/container"
 )
 
 // Close encrypts (and possibly signs) the given data and returns a Letter. Storyline: Close takes an envelope, inserts the message and closes it, resulting in a letter.
-func (s *Session) Close(data []byte) (*Letter, error) { //nolint:gocognit
+func (s *Session) Close(data []byte) (*Letter, error) {
+	return s.CloseWithAD(data, nil)
+}
+
+// CloseWithAD is like Close, but additionally authenticates the given associated data.
+// The associated data binds the letter to an external context, such as a database record ID. It is not part of the letter and must be supplied again when opening or verifying the letter.
+// Empty associated data is the same as none.
+func (s *Session) CloseWithAD(data, ad []byte) (*Letter, error) { //nolint:gocognit
 	var err error
 	var associatedData []byte
-	letter := &Letter{}
+	letter := &Letter{
+		associatedData: ad,
+	}
 
 	if s.wire == nil || s.wire.msgNo == 0 {
 		letter.Version = s.envelope.Version
 		letter.SuiteID = s.envelope.SuiteID
 	}
+	if s.wire != nil && s.wire.concealed {
+		letter.wireFormat = wireFormatConcealed
+	}
+	letter.SignedAt = s.signedAt()
+
+	// Compress data, if it gets smaller.
+	if s.compression != "" {
+		compressed, err := compress(s.compression, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress with %s: %w", s.compression, err)
+		}
+		if len(compressed) < len(data) {
+			data = compressed
+			letter.Compression = s.compression
+		}
+	}
 
 	// Check for additional data in slice, which we should not touch.
 	// TODO: Pre-allocate needed overhead for AEAD and others.
-	if len(data) != cap(data) {
+	if s.padding != nil {
+		// Padding creates a copy of the data.
+		data = s.padding.pad(data)
+		letter.Padded = true
+	} else if len(data) != cap(data) {
 		// Make a copy of the data in order to not modify unrelated data.
 		copiedData := make([]byte, len(data))
 		copy(copiedData, data)
@@ -32,6 +62,15 @@ func (s *Session) Close(data []byte) (*Letter, error) { //nolint:gocognit
 	// key management
 	// ==============
 
+	// the payload sealer does key management and encryption by itself
+	if s.payloadSealer != nil {
+		err = s.sealPayload(letter, data)
+		if err != nil {
+			return nil, err
+		}
+		return letter, nil
+	}
+
 	// create nonce
 	nonce, err := RandomBytes(s.NonceSize())
 	if err != nil {
@@ -55,9 +94,9 @@ func (s *Session) Close(data []byte) (*Letter, error) { //nolint:gocognit
 			}
 
 			// init KDF
-			err = s.kdf.InitKeyDerivation(letter.Nonce, keyMaterial...)
+			err = s.initClosingKeyDerivation(letter, keyMaterial)
 			if err != nil {
-				return nil, fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+				return nil, err
 			}
 		}
 
@@ -139,25 +178,9 @@ func (s *Session) Close(data []byte) (*Letter, error) { //nolint:gocognit
 		}
 
 		// run signers
-		for _, tool := range s.signers {
-			//nolint:scopelint // function is executed immediately within loop
-			err = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
-				sig, err := tool.Sign(data, associatedSigningData, signet)
-				if err != nil {
-					return fmt.Errorf("failed to sign with %s: %w", tool.Info().Name, err)
-				}
-
-				letter.Signatures = append(letter.Signatures, &Seal{
-					Scheme: tool.Info().Name,
-					ID:     signet.ID,
-					Value:  sig,
-				})
-
-				return nil
-			})
-			if err != nil {
-				return nil, err
-			}
+		err = s.signLetter(letter, data, associatedSigningData)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -165,7 +188,32 @@ func (s *Session) Close(data []byte) (*Letter, error) { //nolint:gocognit
 }
 
 // Open decrypts (and possibly verifies) the given letter and returns the original data. Storyline: Open takes a letter, checks any seals, opens it and returns the message.
-func (s *Session) Open(letter *Letter) ([]byte, error) { //nolint:gocognit,gocyclo
+func (s *Session) Open(letter *Letter) ([]byte, error) {
+	return s.OpenWithAD(letter, nil)
+}
+
+// OpenWithAD is like Open, but additionally authenticates the given associated data, which must match the associated data the letter was closed with.
+// See Session.CloseWithAD for details.
+func (s *Session) OpenWithAD(letter *Letter, ad []byte) ([]byte, error) {
+	data, err := s.openLetter(letter, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	// Respond in the concealed wire format and with padding if the peer uses it.
+	// This is only done for authenticated letters, so that forged letters cannot change the session.
+	if s.wire != nil && letter.wireFormat == wireFormatConcealed {
+		s.wire.concealed = true
+	}
+	if s.wire != nil && letter.Padded && s.padding == nil {
+		s.padding = &paddingPolicy{scheme: PaddingPadme}
+	}
+
+	return s.unwrapLetterData(letter, data)
+}
+
+// openLetter verifies and decrypts the given letter and returns the data, which may still be padded and compressed.
+func (s *Session) openLetter(letter *Letter, ad []byte) ([]byte, error) { //nolint:gocognit,gocyclo
 
 	// debugging:
 	/*
@@ -179,6 +227,12 @@ func (s *Session) Open(letter *Letter) ([]byte, error) { //nolint:gocognit,gocyc
 	if s.wire == nil && letter.Version != 1 {
 		return nil, fmt.Errorf("unsupported letter version: %d", letter.Version)
 	}
+	letter.associatedData = ad
+
+	// the payload sealer does key management and decryption by itself
+	if s.payloadSealer != nil {
+		return s.openPayload(letter)
+	}
 
 	// ======
 	// verify
@@ -209,25 +263,9 @@ func (s *Session) Open(letter *Letter) ([]byte, error) { //nolint:gocognit,gocyc
 		}
 
 		// run signers
-		if len(s.envelope.Senders) != len(letter.Signatures) {
-			return nil, errors.New("mismatch regarding available signatures and senders")
-		}
-		sigIndex := 0
-
-		for _, tool := range s.signers {
-			//nolint:scopelint // function is executed immediately within loop
-			err = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
-				err := tool.Verify(data, associatedSigningData, letter.Signatures[sigIndex].Value, signet)
-				if err != nil {
-					return fmt.Errorf("failed to verify signature (%s) with ID %s: %w", tool.Info().Name, letter.Signatures[sigIndex].ID, err)
-				}
-
-				sigIndex++
-				return nil
-			})
-			if err != nil {
-				return nil, err
-			}
+		err = s.verifyLetterSignatures(letter, data, associatedSigningData)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -257,9 +295,9 @@ func (s *Session) Open(letter *Letter) ([]byte, error) { //nolint:gocognit,gocyc
 		}
 
 		// init KDF
-		err = s.kdf.InitKeyDerivation(letter.Nonce, keyMaterial...)
+		err = s.initOpeningKeyDerivation(letter, keyMaterial)
 		if err != nil {
-			return nil, fmt.Errorf("failed to init %s kdf: %w", s.kdf.Info().Name, err)
+			return nil, err
 		}
 	}
 
@@ -321,6 +359,12 @@ func (s *Session) Open(letter *Letter) ([]byte, error) { //nolint:gocognit,gocyc
 
 // Verify verifies signatures of the given letter.
 func (s *Session) Verify(letter *Letter) error {
+	return s.VerifyWithAD(letter, nil)
+}
+
+// VerifyWithAD is like Verify, but additionally authenticates the given associated data, which must match the associated data the letter was closed with.
+// See Session.CloseWithAD for details.
+func (s *Session) VerifyWithAD(letter *Letter, ad []byte) error {
 	// debugging:
 	/*
 		fmt.Printf("opening: %+v\n", letter)
@@ -333,6 +377,7 @@ func (s *Session) Verify(letter *Letter) error {
 	if s.wire == nil && letter.Version != 1 {
 		return fmt.Errorf("unsupported letter version: %d", letter.Version)
 	}
+	letter.associatedData = ad
 
 	// ======
 	// verify
@@ -363,28 +408,185 @@ func (s *Session) Verify(letter *Letter) error {
 		}
 
 		// run signers
-		if len(s.envelope.Senders) != len(letter.Signatures) {
-			return errors.New("mismatch regarding available signatures and senders")
+		err = s.verifyLetterSignatures(letter, data, associatedSigningData)
+		if err != nil {
+			return err
+		}
+	} else {
+		return errors.New("no signatures to verify")
+	}
+
+	return nil
+}
+
+// unwrapLetterData removes the padding from the data and decompresses it, if applicable.
+func (s *Session) unwrapLetterData(letter *Letter, data []byte) ([]byte, error) {
+	var err error
+
+	if letter.Padded {
+		data, err = unpad(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrIntegrityViolation, err)
+		}
+	}
+
+	if letter.Compression != "" {
+		data, err = decompress(letter.Compression, data, s.maxDecompressedSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// sealPayload encrypts the data with the payload sealer for the single recipient of the session.
+// The seal of the recipient holds the encapsulated key and the letter data the ciphertext.
+func (s *Session) sealPayload(letter *Letter, data []byte) error {
+	tool := s.payloadSealer
+
+	// reference the authenticating sender
+	sender := s.authenticatingSender(tool)
+	if sender != nil {
+		letter.Keys = append(letter.Keys, &Seal{
+			Scheme: tool.Info().Name,
+			ID:     sender.ID,
+		})
+	}
+
+	// The session ensures that there is exactly one recipient.
+	return s.envelope.LoopRecipients(tool.Info().Name, func(recipient *Signet) error {
+		var encapsulatedKey, ciphertext []byte
+		var err error
+		if sender != nil {
+			encapsulatedKey, ciphertext, err = tool.SealPayload(data, letter.associatedData, sender, recipient)
+		} else {
+			encapsulatedKey, ciphertext, err = tool.SealPayload(data, letter.associatedData, nil, recipient)
 		}
-		sigIndex := 0
+		if err != nil {
+			return fmt.Errorf("failed to seal data with %s: %w", tool.Info().Name, err)
+		}
+
+		letter.Keys = append(letter.Keys, &Seal{
+			ID:    recipient.ID,
+			Value: encapsulatedKey,
+		})
+		letter.Data = ciphertext
+		return nil
+	})
+}
+
+// openPayload decrypts the data of the letter with the payload sealer.
+func (s *Session) openPayload(letter *Letter) ([]byte, error) {
+	tool := s.payloadSealer
+
+	// Padding and compression would not be authenticated.
+	if letter.Padded || letter.Compression != "" {
+		return nil, fmt.Errorf("%w: padding and compression are not supported by %s", ErrIntegrityViolation, tool.Info().Name)
+	}
 
-		for _, tool := range s.signers {
-			//nolint:scopelint // function is executed immediately within loop
-			err = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
-				err := tool.Verify(data, associatedSigningData, letter.Signatures[sigIndex].Value, signet)
+	// skip the reference to the authenticating sender
+	sealIndex := 0
+	sender := s.authenticatingSender(tool)
+	if sender != nil {
+		if len(letter.Keys) == 0 || letter.Keys[0].ID != sender.ID {
+			return nil, fmt.Errorf("missing sender reference for %s in letter", tool.Info().Name)
+		}
+		sealIndex++
+	}
+	if len(letter.Keys) != sealIndex+1 {
+		return nil, fmt.Errorf("letter must have exactly one seal for the recipient of %s", tool.Info().Name)
+	}
+
+	// The session ensures that there is exactly one recipient.
+	var data []byte
+	err := s.envelope.LoopRecipients(tool.Info().Name, func(signet *Signet) error {
+		var err error
+		if sender != nil {
+			data, err = tool.OpenPayload(letter.Keys[sealIndex].Value, letter.Data, letter.associatedData, signet, sender)
+		} else {
+			data, err = tool.OpenPayload(letter.Keys[sealIndex].Value, letter.Data, letter.associatedData, signet, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: [%s] %w", ErrIntegrityViolation, tool.Info().Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// signLetter signs the letter with all signers and adds the signatures to the letter.
+// Managed signing hashers must already be fed with the signed data.
+func (s *Session) signLetter(letter *Letter, data, associatedSigningData []byte) error {
+	for _, tool := range s.signers {
+		//nolint:scopelint // function is executed immediately within loop
+		err := s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+			sig, err := tool.Sign(data, associatedSigningData, signet)
+			if err != nil {
+				return fmt.Errorf("failed to sign with %s: %w", tool.Info().Name, err)
+			}
+
+			letter.Signatures = append(letter.Signatures, &Seal{
+				Scheme: tool.Info().Name,
+				ID:     signet.ID,
+				Value:  sig,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyLetterSignatures verifies all signatures of the letter.
+// Managed signing hashers must already be fed with the signed data.
+// For counter-signatures, they are fed again with the given data, which is therefore required.
+func (s *Session) verifyLetterSignatures(letter *Letter, data, associatedSigningData []byte) error {
+	if s.signingSenders() != len(letter.Signatures) {
+		return errors.New("mismatch regarding available signatures and senders")
+	}
+	sigIndex := 0
+	hashersFedWithSeal := false
+
+	for _, tool := range s.signers {
+		//nolint:scopelint // function is executed immediately within loop
+		err := s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+			seal := letter.Signatures[sigIndex]
+			err := signet.CheckValidity(seal.signingTime(letter))
+			if err != nil {
+				return fmt.Errorf("failed to verify signature (%s) with ID %s: %w", tool.Info().Name, seal.ID, err)
+			}
+
+			// Counter-signatures additionally sign their signing time.
+			sealSigningData := seal.compileAssociatedSigningData(associatedSigningData)
+			if s.managedSigningHashers != nil && (seal.SignedAt != 0 || hashersFedWithSeal) {
+				s.resetManagedHashers(s.managedSigningHashers)
+				err = s.feedManagedHashers(s.managedSigningHashers, data, sealSigningData)
 				if err != nil {
-					return fmt.Errorf("failed to verify signature (%s) with ID %s: %w", tool.Info().Name, letter.Signatures[sigIndex].ID, err)
+					return err
 				}
+				hashersFedWithSeal = seal.SignedAt != 0
+			}
 
-				sigIndex++
-				return nil
-			})
+			err = tool.Verify(data, sealSigningData, seal.Value, signet)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to verify signature (%s) with ID %s: %w", tool.Info().Name, letter.Signatures[sigIndex].ID, err)
 			}
+
+			sigIndex++
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-	} else {
-		return errors.New("no signatures to verify")
 	}
 
 	return nil
@@ -615,6 +817,9 @@ func (s *Session) setupOpeningKeyMaterial(letter *Letter) ([][]byte, error) {
 				})
 			}
 
+			if sealIndex >= len(letter.Keys) {
+				return errors.New("missing Keys in letter")
+			}
 			unwrappedKey, err := tool.UnwrapKey(letter.Keys[sealIndex].Value, signet)
 			if err != nil {
 				return err
@@ -633,6 +838,31 @@ func (s *Session) setupOpeningKeyMaterial(letter *Letter) ([][]byte, error) {
 	return keyMaterial, nil
 }
 
+// authenticatingSender returns the sender signet that is authenticated by the given payload sealer, if it declares OptionAuthenticatesSender.
+func (s *Session) authenticatingSender(tool tools.ToolLogic) (sender *Signet) {
+	if !tool.Info().HasOption(tools.OptionAuthenticatesSender) {
+		return nil
+	}
+
+	// The session ensures that there is exactly one.
+	_ = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+		sender = signet
+		return nil
+	})
+	return sender
+}
+
+// signingSenders returns the amount of senders that sign the letter.
+func (s *Session) signingSenders() (count int) {
+	for _, tool := range s.signers {
+		_ = s.envelope.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+			count++
+			return nil
+		})
+	}
+	return count
+}
+
 // setup runs the setup function on all tools.
 func (s *Session) setup() error {
 	for _, tool := range s.toolsWithState {
@@ -294,6 +294,66 @@ func testStorage(t *testing.T, suite *Suite) (detectedInvalid bool) { //nolint:t
 	return false
 }
 
+func TestAssociatedData(t *testing.T) {
+	t.Parallel()
+
+	for _, suite := range Suites() {
+		// password suites are slow and already covered by other tests
+		if suiteUsesPassDerivation(suite) && !runComprehensiveTestsActive {
+			continue
+		}
+
+		e, err := setupEnvelopeAndTrustStore(t, suite)
+		if err != nil {
+			t.Fatalf("%s failed: %s", suite.ID, err)
+		}
+		s, err := e.Correspondence(testTrustStore)
+		if err != nil {
+			t.Fatalf("%s failed to init session: %s", suite.ID, err)
+		}
+
+		letter, err := s.CloseWithAD([]byte(testData1), []byte("record-1"))
+		if err != nil {
+			t.Fatalf("%s failed to close: %s", suite.ID, err)
+		}
+		msg, err := letter.ToJSON()
+		if err != nil {
+			t.Fatalf("%s failed to json encode: %s", suite.ID, err)
+		}
+
+		// open with matching associated data
+		letter, err = LetterFromJSON(msg)
+		if err != nil {
+			t.Fatalf("%s failed to json decode: %s", suite.ID, err)
+		}
+		data, err := letter.OpenWithAD(e.suite.Provides, testTrustStore, []byte("record-1"))
+		if err != nil {
+			t.Fatalf("%s failed to open: %s", suite.ID, err)
+		}
+		if string(data) != testData1 {
+			t.Fatalf("%s original data mismatch: %s", suite.ID, string(data))
+		}
+
+		// open with mismatching or missing associated data
+		for _, ad := range [][]byte{[]byte("record-2"), nil} {
+			letter, err = LetterFromJSON(msg)
+			if err != nil {
+				t.Fatalf("%s failed to json decode: %s", suite.ID, err)
+			}
+			_, err = letter.OpenWithAD(e.suite.Provides, testTrustStore, ad)
+			if err == nil {
+				t.Fatalf("%s should fail to open with associated data %q", suite.ID, ad)
+			}
+			if len(letter.Signatures) > 0 {
+				err = letter.VerifyWithAD(e.suite.Provides, testTrustStore, ad)
+				if err == nil {
+					t.Fatalf("%s should fail to verify with associated data %q", suite.ID, ad)
+				}
+			}
+		}
+	}
+}
+
 //nolint:gocognit,gocyclo
 func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 	t.Helper()
@@ -301,7 +361,7 @@ func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 	// check if suite is registered
 	if suite.ID == "" {
 		// register as test suite
-		suite.ID = fmt.Sprintf("__unit_test_suite__" + strings.Join(suite.Tools, "__"))
+		suite.ID = "__unit_test_suite__" + strings.Join(suite.Tools, "__")
 		registerSuite(suite)
 	}
 
@@ -313,6 +373,7 @@ func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 	// check vars
 	keyDerPresent := false
 	passDerPresent := false
+	payloadSealerPresent := false
 	asyncKeyEstablishmentPresent := false
 
 	// process tools and setup envelope
@@ -356,6 +417,15 @@ func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 			}
 			e.Recipients = append(e.Recipients, recipient)
 
+			// add the authenticated sender
+			if tool.Info.HasOption(tools.OptionAuthenticatesSender) {
+				sender, err := getOrMakeSignet(t, tool.StaticLogic, false, fmt.Sprintf("test-%s-sender", tool.Info.Name))
+				if err != nil {
+					return nil, err
+				}
+				e.Senders = append(e.Senders, sender)
+			}
+
 		case tools.PurposeSigning:
 			sender, err := getOrMakeSignet(t, tool.StaticLogic, false, fmt.Sprintf("test-%s", tool.Info.Name))
 			if err != nil {
@@ -375,6 +445,14 @@ func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 			e.suite.Provides.Add(RecipientAuthentication)
 		case tools.PurposeKeyEncapsulation:
 			e.suite.Provides.Add(RecipientAuthentication)
+			if tool.Info.HasOption(tools.OptionAuthenticatesSender) {
+				e.suite.Provides.Add(SenderAuthentication)
+			}
+			if tool.Info.HasOption(tools.OptionSealsPayload) {
+				payloadSealerPresent = true
+				e.suite.Provides.Add(Confidentiality)
+				e.suite.Provides.Add(Integrity)
+			}
 		case tools.PurposeSigning:
 			e.suite.Provides.Add(Integrity)
 			e.suite.Provides.Add(SenderAuthentication)
@@ -401,8 +479,13 @@ func setupEnvelopeAndTrustStore(t *testing.T, suite *Suite) (*Envelope, error) {
 		return nil, testInvalidToolset(e, "authenticating the recipient without using confidentiality does not make sense")
 	}
 
-	// check if we are missing key derivation - this is only ok if we are merely signing
-	if !keyDerPresent && len(e.Senders) != len(e.suite.Tools) {
+	// payload sealers work alone
+	if payloadSealerPresent && len(e.suite.Tools) != 1 {
+		return nil, testInvalidToolset(e, "a payload sealer cannot be combined with other tools")
+	}
+
+	// check if we are missing key derivation - this is only ok if we are merely signing or sealing the payload
+	if !keyDerPresent && !payloadSealerPresent && len(e.Senders) != len(e.suite.Tools) {
 		return nil, testInvalidToolset(e, "omitting a key derivation tool is only allowed when merely signing")
 	}
 
@@ -1,5 +1,7 @@
 package jess
 
+import "time"
+
 var (
 	// Must be var in order decrease for testing for better speed.
 
@@ -21,6 +23,23 @@ var (
 	RecommendedSigning = []string{"Ed25519(SHA2-256)"}
 )
 
+// clock returns the current time. It may be replaced for testing, see SetClock.
+var clock = time.Now
+
+// SetClock sets the clock used to check the validity of signets and to timestamp signatures. Passing nil resets it to the system clock.
+// It is intended for testing and must not be called while jess is in use.
+func SetClock(newClock func() time.Time) {
+	if newClock == nil {
+		newClock = time.Now
+	}
+	clock = newClock
+}
+
+// Now returns the current time of the clock used to check the validity of signets, see SetClock.
+func Now() time.Time {
+	return clock()
+}
+
 // SetMinimumSecurityLevel sets a global minimum security level. Jess will refuse any operations that violate this security level.
 func SetMinimumSecurityLevel(securityLevel int) {
 	defaultSecurityLevel = securityLevel
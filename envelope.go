@@ -36,6 +36,19 @@ type Envelope struct { //nolint:maligned // TODO
 	// SecurityLevel is the security level of the envelope when it was created
 	SecurityLevel int
 
+	// Padding overrides the padding policy of the suite, see PaddingNone and others.
+	// In wire sessions, a peer without a padding policy responds with PADME padding.
+	Padding string `json:",omitempty"`
+
+	// Compression overrides the compression algorithm of the suite, see CompressionNone and others.
+	// Data is only compressed if it gets smaller.
+	Compression string `json:",omitempty"`
+
+	// WrapKey encrypts the data with a random data key, which is wrapped with the key material of the secrets and recipients.
+	// The recipients of such letters can be replaced with Letter.Rewrap without re-encrypting the data.
+	// Letters with a wrapped key cannot be opened by versions of jess that do not support it.
+	WrapKey bool `json:",omitempty"`
+
 	// flag to signify if envelope is used for opening
 	opening bool
 }
@@ -78,6 +91,10 @@ func (e *Envelope) initCorrespondence(trustStore TrustStore, verifying bool) (*S
 
 // WireCorrespondence returns a new wire session (live communication) configured with the envelope.
 func (e *Envelope) WireCorrespondence(trustStore TrustStore) (*Session, error) {
+	if e.WrapKey {
+		return nil, errors.New("key wrapping is not supported in wire sessions")
+	}
+
 	s, err := e.Correspondence(trustStore)
 	if err != nil {
 		return nil, err
@@ -163,6 +180,7 @@ func (e *Envelope) LoopRecipients(scheme string, fn func(*Signet) error) error {
 }
 
 // PrepareSignets checks that all signets of the envelope are ready to use. It will fetch referenced signets and load the keys.
+// When closing, signets outside of their validity period are refused.
 func (e *Envelope) PrepareSignets(storage TrustStore) error {
 	err := e.prepSignets(e.Secrets, e.opening, storage)
 	if err != nil {
@@ -221,9 +239,24 @@ func (e *Envelope) prepSignets(signets []*Signet, recipients bool, storage Trust
 			signets[i] = newSignet
 		}
 
+		// check validity when closing
+		if !e.opening {
+			err := signet.CheckValidity(clock())
+			if err != nil {
+				return err
+			}
+		}
+
 		// unwrap protection
 		if signet.Protection != nil {
-			return errors.New("protected signets are not yet supported")
+			// Unprotect a copy, so that the protected signet is not modified.
+			unprotected := *signet
+			err := unprotected.Unprotect(storage)
+			if err != nil {
+				return fmt.Errorf(`failed to unprotect signet "%s": %w`, signet.ID, err)
+			}
+			signet = &unprotected
+			signets[i] = signet
 		}
 
 		// load signet
@@ -83,6 +83,17 @@ func SignFileData(fileHash *lhash.LabeledHash, metaData map[string]string, envel
 // If an error is returned, there was an error in at least some part of the process.
 // Any returned file data struct must be checked for an verification error.
 func VerifyFileData(letter *jess.Letter, requiredMetaData map[string]string, trustStore jess.TrustStore) (fd *FileData, err error) {
+	return VerifyFileDataWithPolicy(letter, requiredMetaData, nil, trustStore)
+}
+
+// VerifyFileDataWithPolicy is like VerifyFileData, but additionally enforces the given signature policy on the signatures of the letter.
+// Signatures of signers that are not in the trust store are ignored.
+func VerifyFileDataWithPolicy(letter *jess.Letter, requiredMetaData map[string]string, policy *jess.SignaturePolicy, trustStore jess.TrustStore) (fd *FileData, err error) {
+	requirements := fileSigRequirements
+	if policy != nil {
+		requirements = fileSigRequirements.Copy().SetSignaturePolicy(policy)
+	}
+
 	// Parse data.
 	fd = &FileData{
 		signature: letter,
@@ -93,7 +104,7 @@ func VerifyFileData(letter *jess.Letter, requiredMetaData map[string]string, tru
 	}
 
 	// Verify signature and get data.
-	_, err = letter.Open(fileSigRequirements, trustStore)
+	_, err = letter.Open(requirements, trustStore)
 	if err != nil {
 		fd.verificationError = fmt.Errorf("failed to verify file signature: %w", err)
 		return fd, fd.verificationError
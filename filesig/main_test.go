@@ -81,6 +81,99 @@ func testFileSigningWithOptions(t *testing.T, signingMetaData, verifyingMetaData
 	}
 }
 
+func TestFileSigPolicy(t *testing.T) {
+	t.Parallel()
+
+	// Get tool for key generation.
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Generate release keys.
+	var releaseKeys []*jess.Signet
+	for _, id := range []string{"test-release-key-1", "test-release-key-2", "test-release-key-3"} {
+		s, err := getOrMakeSignet(t, tool.StaticLogic, false, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releaseKeys = append(releaseKeys, s)
+	}
+	policy := jess.RequireSigners(2, "test-release-key-1", "test-release-key-2", "test-release-key-3")
+
+	// Sign with first release key.
+	envelope := jess.NewUnconfiguredEnvelope()
+	envelope.SuiteID = jess.SuiteSignV1
+	envelope.Senders = []*jess.Signet{releaseKeys[0]}
+	letter, _, err := SignFileData(lhash.BLAKE2b_256.Digest([]byte(testData1)), nil, envelope, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One signature does not satisfy the policy.
+	_, err = VerifyFileDataWithPolicy(letter, nil, policy, testTrustStore)
+	if !errors.Is(err, jess.ErrSignaturePolicyNotSatisfied) {
+		t.Fatalf("1-of-3 signatures should not satisfy 2-of-3 policy, got: %v", err)
+	}
+
+	// Counter-sign with third release key.
+	err = letter.AddSignature(&jess.Envelope{Senders: []*jess.Signet{releaseKeys[2]}}, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = VerifyFileDataWithPolicy(letter, nil, policy, testTrustStore)
+	if err != nil {
+		t.Fatalf("2-of-3 signatures should satisfy policy: %s", err)
+	}
+
+	// All signers are required.
+	_, err = VerifyFileDataWithPolicy(letter, nil, jess.RequireAllSigners("test-release-key-1", "test-release-key-2"), testTrustStore)
+	if !errors.Is(err, jess.ErrSignaturePolicyNotSatisfied) {
+		t.Fatalf("missing signer should not satisfy policy, got: %v", err)
+	}
+}
+
+func TestFileSigHybrid(t *testing.T) {
+	t.Parallel()
+
+	// Generate classic and post-quantum key pairs.
+	var signets []*jess.Signet
+	for _, toolID := range []string{"Ed25519", "ML-DSA-65"} {
+		tool, err := tools.Get(toolID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-key-filesig-hybrid-"+toolID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signets = append(signets, s)
+	}
+
+	// Sign with both.
+	envelope := jess.NewUnconfiguredEnvelope()
+	envelope.SuiteID = jess.SuiteSignFilePQV1
+	envelope.Senders = signets
+	letter, _, err := SignFileData(lhash.BLAKE2b_256.Digest([]byte(testData1)), nil, envelope, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letter.Signatures) != 2 {
+		t.Fatalf("expected two signatures, got %d", len(letter.Signatures))
+	}
+	_, err = VerifyFileData(letter, nil, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both signatures are required.
+	letter.Signatures = letter.Signatures[:1]
+	_, err = VerifyFileData(letter, nil, testTrustStore)
+	if err == nil {
+		t.Fatal("verification without the post-quantum signature should fail")
+	}
+}
+
 func getOrMakeSignet(t *testing.T, tool tools.ToolLogic, recipient bool, signetID string) (*jess.Signet, error) {
 	t.Helper()
 
@@ -0,0 +1,333 @@
+package filesig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/safing/jess"
+)
+
+// MinisignExtension is the default file extension for minisign signature files.
+const MinisignExtension = ".minisig"
+
+// Minisign format as specified at https://jedisct1.github.io/minisign/.
+const (
+	minisignAlgLegacy         = "Ed" // Signature of the data itself.
+	minisignAlgHashed         = "ED" // Signature of the BLAKE2b-512 hash of the data.
+	minisignKeyIDSize         = 8
+	minisignUntrustedPrefix   = "untrusted comment: "
+	minisignTrustedPrefix     = "trusted comment: "
+	minisignUntrustedSigText  = "signature from jess key "
+	minisignUntrustedKeyText  = "minisign public key "
+	minisignSignatureDataSize = 2 + minisignKeyIDSize + ed25519.SignatureSize
+	minisignPublicKeyDataSize = 2 + minisignKeyIDSize + ed25519.PublicKeySize
+)
+
+// SignFileMinisign signs a file with the Ed25519 sender of the envelope and writes a minisign signature to the signature file, replacing it.
+// The signature can be verified with `minisign -V`, using the public key from MinisignPublicKey.
+// If the trustedComment is empty, the minisign default of the timestamp and file name is used.
+// If the dataFilePath is "-", the file data is read from stdin.
+func SignFileMinisign(dataFilePath, signatureFilePath, trustedComment string, envelope *jess.Envelope, trustStore jess.TrustStore) error {
+	// Get the signing key from the envelope.
+	if err := envelope.LoadSuite(); err != nil {
+		return err
+	}
+	if err := envelope.PrepareSignets(trustStore); err != nil {
+		return err
+	}
+	if len(envelope.Senders) != 1 {
+		return errors.New("minisign signatures require exactly one sender")
+	}
+
+	// Open the data file.
+	var data io.Reader
+	if dataFilePath == "-" {
+		data = os.Stdin
+	} else {
+		file, err := os.Open(dataFilePath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		data = file
+	}
+
+	// Sign the file.
+	if trustedComment == "" {
+		trustedComment = fmt.Sprintf("timestamp:%d\tfile:%s\thashed", jess.Now().Unix(), filepath.Base(dataFilePath))
+	}
+	sigFileData, err := MakeMinisig(data, trustedComment, envelope.Senders[0])
+	if err != nil {
+		return fmt.Errorf("failed to sign file: %w", err)
+	}
+
+	// Write the signature file.
+	return os.WriteFile(signatureFilePath, sigFileData, 0o0644) //nolint:gosec
+}
+
+// MakeMinisig creates a minisign signature of the prehashed data with the given Ed25519 signet, as `minisign -S` does.
+func MakeMinisig(data io.Reader, trustedComment string, signet *jess.Signet) ([]byte, error) {
+	if strings.ContainsAny(trustedComment, "\r\n") {
+		return nil, errors.New("trusted comment must be a single line")
+	}
+	if signet.Scheme != "Ed25519" {
+		return nil, fmt.Errorf("minisign signatures require an Ed25519 signet, not %s", signet.Scheme)
+	}
+	if err := signet.LoadKey(); err != nil {
+		return nil, err
+	}
+	pubKey, ok := signet.PublicKey().(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("signet has an invalid public key")
+	}
+	privKey, ok := signet.PrivateKey().(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("signet has no private key")
+	}
+
+	// Hash and sign the data.
+	hasher, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(hasher, data); err != nil {
+		return nil, fmt.Errorf("failed to hash data: %w", err)
+	}
+	keyID := minisignKeyID(pubKey)
+	sig := ed25519.Sign(privKey, hasher.Sum(nil))
+	globalSig := ed25519.Sign(privKey, append(bytes.Clone(sig), trustedComment...))
+
+	// Serialize the signature.
+	sigData := make([]byte, 0, minisignSignatureDataSize)
+	sigData = append(sigData, minisignAlgHashed...)
+	sigData = append(sigData, keyID...)
+	sigData = append(sigData, sig...)
+
+	var sigFile strings.Builder
+	sigFile.WriteString(minisignUntrustedPrefix + minisignUntrustedSigText + minisignFormatKeyID(keyID) + "\n")
+	sigFile.WriteString(base64.StdEncoding.EncodeToString(sigData) + "\n")
+	sigFile.WriteString(minisignTrustedPrefix + trustedComment + "\n")
+	sigFile.WriteString(base64.StdEncoding.EncodeToString(globalSig) + "\n")
+
+	return []byte(sigFile.String()), nil
+}
+
+// VerifyMinisig verifies a minisign signature of the data, made by the given Ed25519 signet, and returns the trusted comment.
+// Both prehashed and legacy signatures are accepted.
+// As the key IDs of jess signets are derived from the public key, the key ID of the signature is not checked.
+func VerifyMinisig(data io.Reader, sigFileData []byte, signet *jess.Signet) (trustedComment string, err error) {
+	sig, err := parseMinisig(sigFileData)
+	if err != nil {
+		return "", err
+	}
+	signedData, err := sig.signedData(data)
+	if err != nil {
+		return "", err
+	}
+	if err := sig.verify(signedData, signet); err != nil {
+		return "", err
+	}
+	return sig.trustedComment, nil
+}
+
+// VerifyFileMinisign verifies the minisign signature of a file against the Ed25519 recipients of the trust store.
+// It returns the recipient that made the signature and the trusted comment.
+// The trust store must be able to select signets, as the DirTrustStore does.
+// The validity of the recipient is checked at the timestamp of the trusted comment, if present, and at the current time of jess otherwise.
+func VerifyFileMinisign(dataFilePath, signatureFilePath string, trustStore jess.TrustStore) (signet *jess.Signet, trustedComment string, err error) {
+	selector, ok := trustStore.(interface {
+		SelectSignets(filter uint8, schemes ...string) ([]*jess.Signet, error)
+	})
+	if !ok {
+		return nil, "", errors.New("trust store does not support selecting signets")
+	}
+
+	// Read and parse the signature.
+	sigFileData, err := os.ReadFile(signatureFilePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read signature file: %w", err)
+	}
+	sig, err := parseMinisig(sigFileData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Hash the data file.
+	file, err := os.Open(dataFilePath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	signedData, err := sig.signedData(file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check all recipients, as imported keys do not keep their minisign key ID.
+	recipients, err := selector.SelectSignets(jess.FilterRecipientOnly, "Ed25519")
+	if err != nil {
+		return nil, "", err
+	}
+	for _, recipient := range recipients {
+		err := sig.verify(signedData, recipient)
+		switch {
+		case err == nil:
+			// The signature was made by this key, so it must have been valid when signing.
+			if err := recipient.CheckValidity(sig.signingTime()); err != nil {
+				return nil, "", err
+			}
+			return recipient, sig.trustedComment, nil
+		case sig.madeBy(recipient):
+			// Report the error of the recipient that made the signature.
+			return nil, "", err
+		}
+	}
+
+	return nil, "", fmt.Errorf("no recipient in trust store matches minisign signature of key %s", minisignFormatKeyID(sig.keyID))
+}
+
+// MinisignPublicKey returns the public key of an Ed25519 signet in the minisign public key file format, as used by `minisign -V -p`.
+func MinisignPublicKey(signet *jess.Signet) (string, error) {
+	if signet.Scheme != "Ed25519" {
+		return "", fmt.Errorf("minisign keys require an Ed25519 signet, not %s", signet.Scheme)
+	}
+	if err := signet.LoadKey(); err != nil {
+		return "", err
+	}
+	pubKey, ok := signet.PublicKey().(ed25519.PublicKey)
+	if !ok {
+		return "", errors.New("signet has an invalid public key")
+	}
+
+	keyID := minisignKeyID(pubKey)
+	keyData := make([]byte, 0, minisignPublicKeyDataSize)
+	keyData = append(keyData, minisignAlgLegacy...)
+	keyData = append(keyData, keyID...)
+	keyData = append(keyData, pubKey...)
+
+	return minisignUntrustedPrefix + minisignUntrustedKeyText + minisignFormatKeyID(keyID) + "\n" +
+		base64.StdEncoding.EncodeToString(keyData) + "\n", nil
+}
+
+type minisig struct {
+	algorithm      string
+	keyID          []byte
+	signature      []byte
+	trustedComment string
+	globalSig      []byte
+}
+
+func parseMinisig(sigFileData []byte) (*minisig, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(sigFileData), "\r\n", "\n")), "\n")
+	if len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], minisignUntrustedPrefix) ||
+		!strings.HasPrefix(lines[2], minisignTrustedPrefix) {
+		return nil, errors.New("not a minisign signature")
+	}
+
+	sigData, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sigData) != minisignSignatureDataSize {
+		return nil, errors.New("invalid minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return nil, errors.New("invalid minisign global signature")
+	}
+
+	sig := &minisig{
+		algorithm:      string(sigData[:2]),
+		keyID:          sigData[2 : 2+minisignKeyIDSize],
+		signature:      sigData[2+minisignKeyIDSize:],
+		trustedComment: strings.TrimPrefix(lines[2], minisignTrustedPrefix),
+		globalSig:      globalSig,
+	}
+	switch sig.algorithm {
+	case minisignAlgHashed, minisignAlgLegacy:
+		return sig, nil
+	default:
+		return nil, fmt.Errorf("unsupported minisign signature algorithm %q", sig.algorithm)
+	}
+}
+
+// signedData returns the data that is signed: the BLAKE2b-512 hash for prehashed signatures, or the data itself for legacy signatures.
+func (sig *minisig) signedData(data io.Reader) ([]byte, error) {
+	if sig.algorithm == minisignAlgLegacy {
+		return io.ReadAll(data)
+	}
+
+	hasher, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(hasher, data); err != nil {
+		return nil, fmt.Errorf("failed to hash data: %w", err)
+	}
+	return hasher.Sum(nil), nil
+}
+
+func (sig *minisig) verify(signedData []byte, signet *jess.Signet) error {
+	if signet.Scheme != "Ed25519" {
+		return fmt.Errorf("minisign signatures require an Ed25519 signet, not %s", signet.Scheme)
+	}
+	if err := signet.LoadKey(); err != nil {
+		return err
+	}
+	pubKey, ok := signet.PublicKey().(ed25519.PublicKey)
+	if !ok {
+		return errors.New("signet has an invalid public key")
+	}
+
+	if !ed25519.Verify(pubKey, signedData, sig.signature) {
+		return errors.New("minisign signature is invalid")
+	}
+	if !ed25519.Verify(pubKey, append(bytes.Clone(sig.signature), sig.trustedComment...), sig.globalSig) {
+		return errors.New("minisign trusted comment is invalid")
+	}
+	return nil
+}
+
+// signingTime returns the time of the timestamp in the trusted comment, as written by minisign, or the current time if there is none.
+// The trusted comment must already be verified.
+func (sig *minisig) signingTime() time.Time {
+	for _, field := range strings.Split(sig.trustedComment, "\t") {
+		if value, ok := strings.CutPrefix(field, "timestamp:"); ok {
+			if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return time.Unix(timestamp, 0)
+			}
+		}
+	}
+	return jess.Now()
+}
+
+// madeBy returns whether the key ID of the signature is the one derived from the public key of the signet.
+func (sig *minisig) madeBy(signet *jess.Signet) bool {
+	pubKey, ok := signet.PublicKey().(ed25519.PublicKey)
+	return ok && bytes.Equal(sig.keyID, minisignKeyID(pubKey))
+}
+
+// minisignKeyID derives the minisign key ID from the public key, as jess signets do not have one.
+func minisignKeyID(pubKey ed25519.PublicKey) []byte {
+	hash := blake2b.Sum256(pubKey)
+	return hash[:minisignKeyIDSize]
+}
+
+// minisignFormatKeyID formats the key ID as minisign displays it.
+func minisignFormatKeyID(keyID []byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(keyID))
+}
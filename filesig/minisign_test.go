@@ -0,0 +1,226 @@
+package filesig
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/safing/jess"
+	"github.com/safing/jess/tools"
+)
+
+// Test vector created with minisign.
+const (
+	testMinisignPublicKey = "RWRQhGcHOBlzw4CoKyugkk4ioDfoxlXxC9LBx+VNhJ3w9w+cAxgvPsuo"
+	testMinisignData      = "Hello World!\n"
+	testMinisignSignature = `untrusted comment: signature from minisign secret key
+RWRQhGcHOBlzwxrJCyuC+rJfHSfyRKRxkuwa3JJ0bWEs7RHjL1OUmqnTr+V1B9JzFuJIH/ybR2Eus9oEZKt9RbitpF/L4D3+5wg=
+trusted comment: timestamp:1614549543	file:message.txt
+P/722+ynQ+tIy0qadFHwLx5MsyNz/jDKJkDWQj4dDD2OKnVte8m/M14mwPE/1NMwzShPMSBhMXqZGdbe+UZjDg==
+`
+)
+
+func TestMinisign(t *testing.T) {
+	t.Parallel()
+
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-key-minisign-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write "file".
+	dir := t.TempDir()
+	dataFilePath := filepath.Join(dir, "data.txt")
+	sigFilePath := dataFilePath + MinisignExtension
+	err = os.WriteFile(dataFilePath, []byte(testData1), 0o0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := jess.NewUnconfiguredEnvelope()
+	envelope.SuiteID = jess.SuiteSignFileV1
+	envelope.Senders = []*jess.Signet{s}
+	err = SignFileMinisign(dataFilePath, sigFilePath, "", envelope, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigFileData, err := os.ReadFile(sigFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify.
+	trustedComment, err := VerifyMinisig(strings.NewReader(testData1), sigFileData, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(trustedComment, "file:data.txt") {
+		t.Fatalf("unexpected trusted comment %q", trustedComment)
+	}
+	_, err = VerifyMinisig(strings.NewReader(testData1+"x"), sigFileData, s)
+	if err == nil {
+		t.Fatal("verifying modified data should fail")
+	}
+	_, err = VerifyMinisig(
+		strings.NewReader(testData1),
+		[]byte(strings.Replace(string(sigFileData), "file:data.txt", "file:other.txt", 1)),
+		s,
+	)
+	if err == nil {
+		t.Fatal("verifying a modified trusted comment should fail")
+	}
+
+	// Verify with trust store.
+	signedBy, _, err := VerifyFileMinisign(dataFilePath, sigFilePath, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedBy.ID != s.ID {
+		t.Fatalf("signature should be from %s, not %s", s.ID, signedBy.ID)
+	}
+
+	// Trusted comments must be a single line.
+	_, err = MakeMinisig(strings.NewReader(testData1), "a\nb", s)
+	if err == nil {
+		t.Fatal("multi-line trusted comment should fail")
+	}
+
+	// The public key must be in the minisign format.
+	pubKey, err := MinisignPublicKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(pubKey, "untrusted comment: minisign public key ") ||
+		strings.Count(pubKey, "\n") != 2 {
+		t.Fatalf("unexpected public key format: %q", pubKey)
+	}
+}
+
+func TestMinisignKeyValidity(t *testing.T) {
+	t.Parallel()
+
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-key-minisign-expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write "file" and sign it.
+	dir := t.TempDir()
+	dataFilePath := filepath.Join(dir, "data.txt")
+	sigFilePath := dataFilePath + MinisignExtension
+	err = os.WriteFile(dataFilePath, []byte(testData1), 0o0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := jess.NewUnconfiguredEnvelope()
+	envelope.SuiteID = jess.SuiteSignFileV1
+	envelope.Senders = []*jess.Signet{s}
+
+	// Limit the validity of the key in the trust store.
+	now := time.Now()
+	rcpt, err := testTrustStore.GetSignet(s.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limitedRcpt := *rcpt
+	limitedRcpt.Info = &jess.SignetInfo{
+		NotBefore: now.Add(-3 * time.Hour),
+		Expires:   now.Add(-time.Hour),
+	}
+	err = testTrustStore.StoreSignet(&limitedRcpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign and verify with different timestamps in the trusted comment.
+	for _, test := range []struct {
+		name           string
+		trustedComment string
+		expectedErr    error
+	}{
+		{
+			name:           "timestamp inside validity window",
+			trustedComment: fmt.Sprintf("timestamp:%d\tfile:data.txt", now.Add(-2*time.Hour).Unix()),
+		},
+		{
+			name:           "timestamp before validity window",
+			trustedComment: fmt.Sprintf("timestamp:%d\tfile:data.txt", now.Add(-4*time.Hour).Unix()),
+			expectedErr:    jess.ErrSignetNotYetValid,
+		},
+		{
+			name:           "timestamp after validity window",
+			trustedComment: fmt.Sprintf("timestamp:%d\tfile:data.txt", now.Add(-30*time.Minute).Unix()),
+			expectedErr:    jess.ErrSignetExpired,
+		},
+		{
+			name:           "no timestamp",
+			trustedComment: "file:data.txt",
+			expectedErr:    jess.ErrSignetExpired,
+		},
+		{
+			name:           "malformed timestamp",
+			trustedComment: fmt.Sprintf("timestamp:%d.5\tfile:data.txt", now.Add(-2*time.Hour).Unix()),
+			expectedErr:    jess.ErrSignetExpired,
+		},
+	} {
+		err = SignFileMinisign(dataFilePath, sigFilePath, test.trustedComment, envelope, testTrustStore)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		_, trustedComment, err := VerifyFileMinisign(dataFilePath, sigFilePath, testTrustStore)
+		switch {
+		case test.expectedErr == nil && err != nil:
+			t.Errorf("%s: verification should succeed: %s", test.name, err)
+		case test.expectedErr != nil && !errors.Is(err, test.expectedErr):
+			t.Errorf("%s: verification should fail with %s, not %v", test.name, test.expectedErr, err)
+		case err == nil && trustedComment != test.trustedComment:
+			t.Errorf("%s: unexpected trusted comment %q", test.name, trustedComment)
+		}
+	}
+}
+
+func TestMinisignVector(t *testing.T) {
+	t.Parallel()
+
+	// Import the minisign public key.
+	keyData, err := base64.StdEncoding.DecodeString(testMinisignPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeyData, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(keyData[10:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet, err := jess.SignetFromPEM(pem.EncodeToMemory(&pem.Block{Type: jess.PEMPublicKeyType, Bytes: pubKeyData}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the minisign signature.
+	trustedComment, err := VerifyMinisig(strings.NewReader(testMinisignData), []byte(testMinisignSignature), signet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trustedComment != "timestamp:1614549543\tfile:message.txt" {
+		t.Fatalf("unexpected trusted comment %q", trustedComment)
+	}
+	_, err = VerifyMinisig(strings.NewReader(testMinisignData+"x"), []byte(testMinisignSignature), signet)
+	if err == nil {
+		t.Fatal("verifying modified data should fail")
+	}
+}
@@ -0,0 +1,215 @@
+package filesig
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/safing/jess"
+)
+
+// SSHSig holds defaults for SSH signatures.
+const (
+	// SSHSigExtension is the default file extension for SSH signature files.
+	SSHSigExtension = ".sshsig"
+	// SSHSigDefaultNamespace is the default namespace of SSH signatures of files.
+	SSHSigDefaultNamespace = "file"
+)
+
+// SSHSIG format as specified in PROTOCOL.sshsig of OpenSSH.
+const (
+	sshSigMagic         = "SSHSIG"
+	sshSigVersion       = 1
+	sshSigHashAlgorithm = "sha512"
+	sshSigArmorStart    = "-----BEGIN SSH SIGNATURE-----"
+	sshSigArmorEnd      = "-----END SSH SIGNATURE-----"
+	sshSigLineLength    = 70
+)
+
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+type sshSigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// SignFileSSH signs a file with the Ed25519 sender of the envelope and writes an SSH signature to the signature file, replacing it.
+// The signature can be verified with `ssh-keygen -Y verify`.
+// If the dataFilePath is "-", the file data is read from stdin.
+func SignFileSSH(dataFilePath, signatureFilePath, namespace string, envelope *jess.Envelope, trustStore jess.TrustStore) error {
+	// Get the signing key from the envelope.
+	if err := envelope.LoadSuite(); err != nil {
+		return err
+	}
+	if err := envelope.PrepareSignets(trustStore); err != nil {
+		return err
+	}
+	if len(envelope.Senders) != 1 {
+		return errors.New("SSH signatures require exactly one sender")
+	}
+
+	// Open the data file.
+	var data io.Reader
+	if dataFilePath == "-" {
+		data = os.Stdin
+	} else {
+		file, err := os.Open(dataFilePath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		data = file
+	}
+
+	// Sign the file.
+	sigFileData, err := MakeSSHSig(data, namespace, envelope.Senders[0])
+	if err != nil {
+		return fmt.Errorf("failed to sign file: %w", err)
+	}
+
+	// Write the signature file.
+	return os.WriteFile(signatureFilePath, sigFileData, 0o0644) //nolint:gosec
+}
+
+// MakeSSHSig creates an armored SSH signature of the data with the given Ed25519 signet, as `ssh-keygen -Y sign` does.
+func MakeSSHSig(data io.Reader, namespace string, signet *jess.Signet) ([]byte, error) {
+	if namespace == "" {
+		return nil, errors.New("missing namespace")
+	}
+	if signet.Scheme != "Ed25519" {
+		return nil, fmt.Errorf("SSH signatures require an Ed25519 signet, not %s", signet.Scheme)
+	}
+	if err := signet.LoadKey(); err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(signet.PrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	// Hash and sign the data.
+	signedData, err := makeSSHSigSignedData(data, namespace)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(jess.Random(), signedData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serialize and armor the signature.
+	blob := append([]byte(sshSigMagic), ssh.Marshal(&sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: sshSigHashAlgorithm,
+		Signature:     ssh.Marshal(sig),
+	})...)
+	encodedData := base64.StdEncoding.EncodeToString(blob)
+
+	var armored strings.Builder
+	armored.WriteString(sshSigArmorStart + "\n")
+	for len(encodedData) > 0 {
+		n := min(len(encodedData), sshSigLineLength)
+		armored.WriteString(encodedData[:n] + "\n")
+		encodedData = encodedData[n:]
+	}
+	armored.WriteString(sshSigArmorEnd + "\n")
+
+	return []byte(armored.String()), nil
+}
+
+// VerifySSHSig verifies an armored SSH signature of the data, made by the given Ed25519 signet.
+// As SSH signatures hold no timestamp, the validity of the signet is checked at the current time of jess.
+func VerifySSHSig(data io.Reader, sigFileData []byte, namespace string, signet *jess.Signet) error {
+	// Remove the armor.
+	sigFileData = bytes.TrimSpace(sigFileData)
+	if !bytes.HasPrefix(sigFileData, []byte(sshSigArmorStart)) ||
+		!bytes.HasSuffix(sigFileData, []byte(sshSigArmorEnd)) {
+		return errors.New("not an SSH signature")
+	}
+	sigFileData = sigFileData[len(sshSigArmorStart) : len(sigFileData)-len(sshSigArmorEnd)]
+	blob, err := base64.StdEncoding.DecodeString(string(whitespaceMatcher.ReplaceAll(sigFileData, nil)))
+	if err != nil {
+		return fmt.Errorf("failed to decode SSH signature: %w", err)
+	}
+
+	// Parse the signature.
+	if !bytes.HasPrefix(blob, []byte(sshSigMagic)) {
+		return errors.New("not an SSH signature")
+	}
+	sigBlob := &sshSigBlob{}
+	if err := ssh.Unmarshal(blob[len(sshSigMagic):], sigBlob); err != nil {
+		return fmt.Errorf("failed to parse SSH signature: %w", err)
+	}
+	switch {
+	case sigBlob.Version != sshSigVersion:
+		return fmt.Errorf("unsupported SSH signature version %d", sigBlob.Version)
+	case sigBlob.Namespace != namespace:
+		return fmt.Errorf("SSH signature is for namespace %q, not %q", sigBlob.Namespace, namespace)
+	case sigBlob.HashAlgorithm != sshSigHashAlgorithm:
+		return fmt.Errorf("unsupported SSH signature hash algorithm %q", sigBlob.HashAlgorithm)
+	}
+	sig := &ssh.Signature{}
+	if err := ssh.Unmarshal(sigBlob.Signature, sig); err != nil {
+		return fmt.Errorf("failed to parse SSH signature: %w", err)
+	}
+
+	// Check the public key.
+	if signet.Scheme != "Ed25519" {
+		return fmt.Errorf("SSH signatures require an Ed25519 signet, not %s", signet.Scheme)
+	}
+	if err := signet.LoadKey(); err != nil {
+		return err
+	}
+	pubKey, err := ssh.NewPublicKey(signet.PublicKey())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pubKey.Marshal(), sigBlob.PublicKey) {
+		return errors.New("SSH signature was made by a different key")
+	}
+
+	// Verify the signature.
+	signedData, err := makeSSHSigSignedData(data, namespace)
+	if err != nil {
+		return err
+	}
+	if err := pubKey.Verify(signedData, sig); err != nil {
+		return err
+	}
+
+	// The signature was made by this key, so it must be valid.
+	return signet.CheckValidity(jess.Now())
+}
+
+func makeSSHSigSignedData(data io.Reader, namespace string) ([]byte, error) {
+	hasher := sha512.New()
+	if _, err := io.Copy(hasher, data); err != nil {
+		return nil, fmt.Errorf("failed to hash data: %w", err)
+	}
+
+	return append([]byte(sshSigMagic), ssh.Marshal(&sshSigSignedData{
+		Namespace:     namespace,
+		HashAlgorithm: sshSigHashAlgorithm,
+		Hash:          hasher.Sum(nil),
+	})...), nil
+}
@@ -0,0 +1,97 @@
+package filesig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/safing/jess"
+	"github.com/safing/jess/tools"
+)
+
+func TestSSHSig(t *testing.T) {
+	t.Parallel()
+
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-key-sshsig-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write "file" and sign it.
+	dir := t.TempDir()
+	dataFilePath := filepath.Join(dir, "data.txt")
+	sigFilePath := dataFilePath + SSHSigExtension
+	err = os.WriteFile(dataFilePath, []byte(testData1), 0o0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := jess.NewUnconfiguredEnvelope()
+	envelope.SuiteID = jess.SuiteSignFileV1
+	envelope.Senders = []*jess.Signet{s}
+	err = SignFileSSH(dataFilePath, sigFilePath, SSHSigDefaultNamespace, envelope, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigFileData, err := os.ReadFile(sigFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify.
+	err = VerifySSHSig(strings.NewReader(testData1), sigFileData, SSHSigDefaultNamespace, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifySSHSig(strings.NewReader(testData1+"x"), sigFileData, SSHSigDefaultNamespace, s)
+	if err == nil {
+		t.Fatal("verifying modified data should fail")
+	}
+	err = VerifySSHSig(strings.NewReader(testData1), sigFileData, "other", s)
+	if err == nil {
+		t.Fatal("verifying with a different namespace should fail")
+	}
+	expired := *s
+	expired.Info = &jess.SignetInfo{
+		Expires: time.Now().Add(-time.Hour),
+	}
+	err = VerifySSHSig(strings.NewReader(testData1), sigFileData, SSHSigDefaultNamespace, &expired)
+	if !errors.Is(err, jess.ErrSignetExpired) {
+		t.Fatalf("verifying with an expired key should fail with %s, not %v", jess.ErrSignetExpired, err)
+	}
+
+	// Verify with OpenSSH, if available.
+	sshKeygen, err := exec.LookPath("ssh-keygen")
+	if err != nil {
+		t.Skip("ssh-keygen not available, skipping OpenSSH verification")
+	}
+	pubKey, err := s.SSHPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedSignersPath := filepath.Join(dir, "allowed_signers")
+	err = os.WriteFile(allowedSignersPath, []byte("test@example.com "+pubKey+"\n"), 0o0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command( //nolint:gosec
+		sshKeygen, "-Y", "verify",
+		"-f", allowedSignersPath,
+		"-I", "test@example.com",
+		"-n", SSHSigDefaultNamespace,
+		"-s", sigFilePath,
+	)
+	cmd.Stdin = bytes.NewReader([]byte(testData1))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("ssh-keygen failed to verify signature: %s: %s", err, output)
+	}
+}
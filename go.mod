@@ -1,12 +1,15 @@
 module github.com/safing/jess
 
-go 1.21.1
+go 1.22.0
 
 toolchain go1.22.3
 
 require (
+	filippo.io/age v1.2.1
 	github.com/AlecAivazis/survey/v2 v2.3.7
 	github.com/aead/ecdh v0.2.0
+	github.com/cloudflare/circl v1.6.3
+	github.com/decred/dcrd/bech32 v1.1.4
 	github.com/mr-tron/base58 v1.2.0
 	github.com/safing/structures v1.1.0
 	github.com/satori/go.uuid v1.2.0
@@ -16,9 +19,10 @@ require (
 	github.com/tidwall/gjson v1.17.1
 	github.com/tidwall/pretty v1.2.1
 	github.com/tidwall/sjson v1.2.5
+	github.com/tink-crypto/tink-go/v2 v2.3.0
 	github.com/zalando/go-keyring v0.2.5
 	github.com/zeebo/blake3 v0.2.3
-	golang.org/x/crypto v0.24.0
+	golang.org/x/crypto v0.31.0
 	golang.org/x/exp v0.0.0-20240613232115-7f521ea00fb8
 )
 
@@ -42,9 +46,10 @@ require (
 	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
-	golang.org/x/sys v0.21.0 // indirect
-	golang.org/x/term v0.21.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/term v0.27.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/protobuf v1.36.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
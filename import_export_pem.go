@@ -0,0 +1,213 @@
+package jess
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/safing/jess/tools"
+)
+
+// PEM block types for the PEM format.
+const (
+	PEMPrivateKeyType  = "PRIVATE KEY"
+	PEMPublicKeyType   = "PUBLIC KEY"
+	PEMCertificateType = "CERTIFICATE"
+
+	pemBeginPrefix = "-----BEGIN "
+)
+
+// IsPEM returns whether the text looks like a PEM encoded private key, public key or certificate.
+func IsPEM(text string) bool {
+	return strings.HasPrefix(text, pemBeginPrefix+PEMPrivateKeyType+"-----") ||
+		strings.HasPrefix(text, pemBeginPrefix+PEMPublicKeyType+"-----") ||
+		strings.HasPrefix(text, pemBeginPrefix+PEMCertificateType+"-----")
+}
+
+// SignetFromPEM imports the first PEM block of the data as a signet.
+// Supported are PKCS#8 private keys ("PRIVATE KEY"), SubjectPublicKeyInfo public keys ("PUBLIC KEY") and the public keys of X.509 certificates ("CERTIFICATE").
+// If scheme is empty, it is derived from the key type: Ed25519 keys become Ed25519, X25519 keys ECDH-X25519 and NIST curve keys the ECDH-P* scheme of their curve.
+// RSA keys require the scheme to be set, as well as NIST curve keys for use with the ECDSA tools.
+// The keys are converted by the tool of the scheme, which fails for tools that do not support the key types of the standard library.
+func SignetFromPEM(pemData []byte, scheme string) (*Signet, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	// parse keys
+	var (
+		pubKey  crypto.PublicKey
+		privKey crypto.PrivateKey
+		info    *SignetInfo
+		err     error
+	)
+	switch block.Type {
+	case PEMPrivateKeyType:
+		privKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+		}
+		switch k := privKey.(type) {
+		case *ecdh.PrivateKey:
+			pubKey = k.PublicKey()
+		case crypto.Signer:
+			pubKey = k.Public()
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", privKey)
+		}
+	case PEMPublicKeyType:
+		pubKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+	case PEMCertificateType:
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		pubKey = cert.PublicKey
+		info = &SignetInfo{
+			Name:      cert.Subject.CommonName,
+			NotBefore: cert.NotBefore,
+			Expires:   cert.NotAfter,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	// derive scheme
+	if scheme == "" {
+		scheme, err = pemDefaultScheme(pubKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// create signet
+	signet := &Signet{
+		Version: 1,
+		Scheme:  scheme,
+		Public:  privKey == nil,
+		Info:    info,
+	}
+	err = signet.loadTool()
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to the key types of the tool
+	err = signet.tool.StaticLogic.ImportStdKeys(signet, pubKey, privKey)
+	switch {
+	case errors.Is(err, tools.ErrNotImplemented):
+		return nil, fmt.Errorf("scheme %s does not support PEM", scheme)
+	case errors.Is(err, tools.ErrInvalidKey):
+		return nil, fmt.Errorf("key type %T cannot be used with %s", pubKey, scheme)
+	case err != nil:
+		return nil, err
+	}
+
+	// serialize and load again, so that the tool checks the keys
+	err = signet.StoreKey()
+	if err != nil {
+		return nil, err
+	}
+	signet.loadedPublicKey = nil
+	signet.loadedPrivateKey = nil
+	err = signet.LoadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = signet.AssignUUID()
+	if err != nil {
+		return nil, err
+	}
+	return signet, nil
+}
+
+// ExportPEM exports the public key of a signet as a PEM encoded SubjectPublicKeyInfo.
+func (signet *Signet) ExportPEM() ([]byte, error) {
+	pubKey, _, err := signet.pemKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := x509.MarshalPKIXPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  PEMPublicKeyType,
+		Bytes: data,
+	}), nil
+}
+
+// BackupPEM exports the private key of a signet as a PEM encoded PKCS#8 private key.
+func (signet *Signet) BackupPEM() ([]byte, error) {
+	if signet.Public {
+		return nil, errors.New("cannot backup (only export) a recipient")
+	}
+	_, privKey, err := signet.pemKeys()
+	if err != nil {
+		return nil, err
+	}
+	if privKey == nil {
+		return nil, errors.New("signet has no private key")
+	}
+
+	data, err := x509.MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  PEMPrivateKeyType,
+		Bytes: data,
+	}), nil
+}
+
+// pemKeys loads the keys of the signet and converts them to the key types of the x509 package.
+func (signet *Signet) pemKeys() (pubKey crypto.PublicKey, privKey crypto.PrivateKey, err error) {
+	switch signet.Scheme {
+	case SignetSchemeKey, SignetSchemePassword:
+		return nil, nil, errors.New("keys and passwords cannot be exported as PEM")
+	}
+	err = signet.LoadKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = signet.loadTool()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pubKey, privKey, err = signet.tool.StaticLogic.ExportStdKeys(signet)
+	if errors.Is(err, tools.ErrNotImplemented) {
+		return nil, nil, fmt.Errorf("scheme %s does not support PEM", signet.Scheme)
+	}
+	return pubKey, privKey, err
+}
+
+func pemDefaultScheme(pubKey crypto.PublicKey) (string, error) {
+	switch k := pubKey.(type) {
+	case ed25519.PublicKey:
+		return "Ed25519", nil
+	case *ecdh.PublicKey:
+		if k.Curve() == ecdh.X25519() {
+			return "ECDH-X25519", nil
+		}
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDH-P%d", k.Curve.Params().BitSize), nil
+	case *rsa.PublicKey:
+		return "", errors.New("RSA keys require a scheme, such as RSA-OAEP or RSA-PSS")
+	}
+
+	return "", fmt.Errorf("unsupported key type %T", pubKey)
+}
@@ -0,0 +1,242 @@
+package jess
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/safing/jess/tools"
+)
+
+var pemTestSchemes = []string{
+	"Ed25519",
+	"ECDH-X25519",
+	"ECDH-P224",
+	"ECDH-P256",
+	"ECDH-P384",
+	"ECDH-P521",
+	"ECDSA-P256",
+	"RSA-OAEP",
+	"RSA-PSS",
+}
+
+func TestPEMRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, scheme := range pemTestSchemes {
+		tool, err := tools.Get(scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signet, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-pem-"+scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// private key
+		privPEM, err := signet.BackupPEM()
+		if err != nil {
+			t.Fatalf("%s: failed to backup: %s", scheme, err)
+		}
+		imported, err := SignetFromPEM(privPEM, scheme)
+		if err != nil {
+			t.Fatalf("%s: failed to import private key: %s", scheme, err)
+		}
+		if imported.Public || imported.Scheme != scheme {
+			t.Fatalf("%s: unexpected signet: public=%v scheme=%s", scheme, imported.Public, imported.Scheme)
+		}
+		reexported, err := imported.BackupPEM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privPEM, reexported) {
+			t.Fatalf("%s: private key changed after import", scheme)
+		}
+
+		// public key
+		pubPEM, err := signet.ExportPEM()
+		if err != nil {
+			t.Fatalf("%s: failed to export: %s", scheme, err)
+		}
+		imported, err = SignetFromPEM(pubPEM, scheme)
+		if err != nil {
+			t.Fatalf("%s: failed to import public key: %s", scheme, err)
+		}
+		if !imported.Public {
+			t.Fatalf("%s: imported public key should be a recipient", scheme)
+		}
+		reexported, err = imported.ExportPEM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pubPEM, reexported) {
+			t.Fatalf("%s: public key changed after import", scheme)
+		}
+		_, err = imported.BackupPEM()
+		if err == nil {
+			t.Fatalf("%s: recipient should not have a private key backup", scheme)
+		}
+
+		// the imported key must load with the tool after serialization
+		loaded, err := SignetFromBase58(mustToBase58(t, imported))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = loaded.LoadKey()
+		if err != nil {
+			t.Fatalf("%s: failed to load imported key: %s", scheme, err)
+		}
+	}
+
+	// RSA keys need a scheme, others do not
+	rsaSignet, err := testTrustStore.GetSignet("test-pem-RSA-OAEP", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM, err := rsaSignet.ExportPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SignetFromPEM(rsaPEM, "")
+	if err == nil {
+		t.Fatal("RSA key without scheme should fail")
+	}
+	p256Signet, err := testTrustStore.GetSignet("test-pem-ECDSA-P256", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256PEM, err := p256Signet.ExportPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := SignetFromPEM(p256PEM, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Scheme != "ECDH-P256" {
+		t.Fatalf("unexpected default scheme %s", imported.Scheme)
+	}
+
+	// keys must not be used with the wrong scheme
+	_, err = SignetFromPEM(p256PEM, "ECDH-P384")
+	if err == nil {
+		t.Fatal("P-256 key should not be accepted for ECDH-P384")
+	}
+	_, err = SignetFromPEM(rsaPEM, "Ed25519")
+	if err == nil {
+		t.Fatal("RSA key should not be accepted for Ed25519")
+	}
+
+	// tools without support for standard key types must fail
+	tool, err := tools.Get("Ed448")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed448Signet, err := getOrMakeSignet(t, tool.StaticLogic, false, "test-pem-Ed448")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ed448Signet.ExportPEM()
+	if err == nil || !strings.Contains(err.Error(), "does not support PEM") {
+		t.Fatalf("exporting Ed448 key should fail as unsupported, got: %v", err)
+	}
+	_, err = SignetFromPEM(p256PEM, "Ed448")
+	if err == nil || !strings.Contains(err.Error(), "does not support PEM") {
+		t.Fatalf("importing as Ed448 key should fail as unsupported, got: %v", err)
+	}
+}
+
+func TestPEMCertificate(t *testing.T) {
+	t.Parallel()
+
+	pubKey, privKey, err := ed25519.GenerateKey(Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Now().Add(-time.Hour).Truncate(time.Second)
+	notAfter := notBefore.Add(24 * time.Hour)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Release Signing"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	certData, err := x509.CreateCertificate(Random(), template, template, pubKey, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signet, err := SignetFromPEM(pem.EncodeToMemory(&pem.Block{Type: PEMCertificateType, Bytes: certData}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case !signet.Public || signet.Scheme != "Ed25519":
+		t.Fatalf("unexpected signet: public=%v scheme=%s", signet.Public, signet.Scheme)
+	case !pubKey.Equal(signet.PublicKey()):
+		t.Fatal("public key does not match certificate")
+	case signet.Info.Name != "Release Signing":
+		t.Fatalf("unexpected name %q", signet.Info.Name)
+	case !signet.Info.NotBefore.Equal(notBefore) || !signet.Info.Expires.Equal(notAfter):
+		t.Fatal("validity does not match certificate")
+	}
+}
+
+func TestPEMOpenSSL(t *testing.T) {
+	t.Parallel()
+
+	openssl, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("openssl not available")
+	}
+
+	for _, test := range []struct {
+		args        []string
+		scheme      string
+		needsScheme bool
+	}{
+		{[]string{"-algorithm", "ED25519"}, "Ed25519", false},
+		{[]string{"-algorithm", "X25519"}, "ECDH-X25519", false},
+		{[]string{"-algorithm", "EC", "-pkeyopt", "ec_paramgen_curve:P-384"}, "ECDH-P384", false},
+		{[]string{"-algorithm", "RSA", "-pkeyopt", "rsa_keygen_bits:2048"}, "RSA-PSS", true},
+	} {
+		// generate private key with openssl
+		privPEM, err := exec.Command(openssl, append([]string{"genpkey"}, test.args...)...).Output() //nolint:gosec
+		if err != nil {
+			t.Fatalf("%s: openssl failed: %s", test.scheme, err)
+		}
+		var scheme string
+		if test.needsScheme {
+			scheme = test.scheme
+		}
+		signet, err := SignetFromPEM(privPEM, scheme)
+		if err != nil {
+			t.Fatalf("%s: failed to import openssl key: %s", test.scheme, err)
+		}
+		if signet.Scheme != test.scheme {
+			t.Fatalf("unexpected scheme %s, expected %s", signet.Scheme, test.scheme)
+		}
+
+		// openssl must derive the same public key
+		cmd := exec.Command(openssl, "pkey", "-pubout") //nolint:gosec
+		cmd.Stdin = bytes.NewReader(privPEM)
+		opensslPubPEM, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%s: openssl failed: %s", test.scheme, err)
+		}
+		pubPEM, err := signet.ExportPEM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pubPEM, opensslPubPEM) {
+			t.Fatalf("%s: public key does not match openssl:\n%s\n%s", test.scheme, pubPEM, opensslPubPEM)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/safing/jess/tools"
+)
+
+// LetterDescription holds structured details about a letter, gathered without opening it.
+type LetterDescription struct {
+	Version uint8
+	SuiteID string
+	// Suite is the suite of the letter. It is nil if the suite is unknown or the letter does not specify one.
+	Suite *Suite
+
+	// Keys describes the key seals, ie. the recipients, secrets and ephemeral keys.
+	Keys []*SealDescription
+	// Signatures describes the signatures and their signers.
+	Signatures []*SealDescription
+
+	// Authenticated reports whether the suite authenticates the data with an integrated cipher, a MAC or a tool that seals the payload. Signatures are described separately.
+	Authenticated bool
+	Padded        bool
+	Compression   string
+	// KeyWrapped reports whether the data key is wrapped, so that the key seals can be replaced with Letter.Rewrap.
+	KeyWrapped bool
+	// SignedAt is the time the letter was signed at, if specified.
+	SignedAt time.Time
+
+	// DataSize is the size of the payload in the letter, which may be encrypted, padded and compressed.
+	DataSize int
+}
+
+// SealDescription describes a seal within a letter.
+type SealDescription struct {
+	Scheme string
+	ID     string
+
+	// Info holds information about the signet referenced by the seal, if it was found in the trust store.
+	Info *SignetInfo
+	// SignedAt is the time a counter-signature was made at, if specified.
+	SignedAt time.Time
+}
+
+// Describe returns structured details about the letter without opening it.
+// If a trust store is given, seal IDs are resolved against it. The returned information is not authenticated.
+func (letter *Letter) Describe(trustStore TrustStore) *LetterDescription {
+	desc := &LetterDescription{
+		Version:     letter.Version,
+		SuiteID:     letter.SuiteID,
+		Padded:      letter.Padded,
+		Compression: letter.Compression,
+		KeyWrapped:  len(letter.WrappedKey) > 0,
+		DataSize:    len(letter.Data),
+	}
+
+	if letter.SignedAt != 0 {
+		desc.SignedAt = time.Unix(letter.SignedAt, 0)
+	}
+	if letter.SuiteID != "" {
+		if suite, ok := GetSuite(letter.SuiteID); ok {
+			desc.Suite = suite
+			desc.Authenticated = suiteAuthenticatesData(suite)
+		}
+	}
+
+	desc.Keys = describeSeals(letter.Keys, trustStore)
+	desc.Signatures = describeSeals(letter.Signatures, trustStore)
+
+	return desc
+}
+
+// DescribeLetterFile returns structured details about a letter in File Format Version 2 without opening it.
+// The data size is the total size of all chunks. See Letter.Describe for details.
+func DescribeLetterFile(r io.ReaderAt, trustStore TrustStore) (*LetterDescription, error) {
+	reader := &letterFileReader{r: r}
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// skip chunks
+	var dataSize int64
+	for index := 0; ; index++ {
+		chunkSize, last, err := reader.skipChunk()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, errors.New("file ended before last chunk")
+			}
+			return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+		dataSize += chunkSize
+
+		if last {
+			break
+		}
+	}
+
+	// read signatures
+	letter.Signatures, err = readStreamSignatures(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file signatures: %w", err)
+	}
+
+	desc := letter.Describe(trustStore)
+	desc.DataSize = int(dataSize)
+	return desc, nil
+}
+
+// suiteAuthenticatesData returns whether the suite uses an integrated cipher, a MAC or a payload sealer, which authenticate the data of letters and every chunk of letter files.
+func suiteAuthenticatesData(suite *Suite) bool {
+	for _, toolID := range suite.Tools {
+		tool, err := tools.Get(strings.Split(toolID, "(")[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		case tool.Info.Purpose == tools.PurposeIntegratedCipher,
+			tool.Info.Purpose == tools.PurposeMAC,
+			tool.Info.HasOption(tools.OptionSealsPayload):
+			return true
+		}
+	}
+	return false
+}
+
+func describeSeals(seals []*Seal, trustStore TrustStore) []*SealDescription {
+	if len(seals) == 0 {
+		return nil
+	}
+
+	descs := make([]*SealDescription, 0, len(seals))
+	for _, seal := range seals {
+		desc := &SealDescription{
+			Scheme: seal.Scheme,
+			ID:     seal.ID,
+			Info:   lookupSignetInfo(seal.ID, trustStore),
+		}
+		if seal.SignedAt != 0 {
+			desc.SignedAt = time.Unix(seal.SignedAt, 0)
+		}
+		descs = append(descs, desc)
+	}
+	return descs
+}
+
+// lookupSignetInfo returns the info of the signet with the given ID, preferring the public version.
+func lookupSignetInfo(id string, trustStore TrustStore) *SignetInfo {
+	if id == "" || trustStore == nil {
+		return nil
+	}
+
+	for _, recipient := range []bool{true, false} {
+		signet, err := trustStore.GetSignet(id, recipient)
+		if err == nil && signet.Info != nil {
+			return signet.Info
+		}
+	}
+	return nil
+}
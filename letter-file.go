@@ -1,7 +1,10 @@
 package jess
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/safing/structures/container"
 	"github.com/safing/structures/dsd"
@@ -13,9 +16,25 @@ import (
 - File Format Version: varint
 - Header: Letter without Data as byte block
 - Data: byte block
+
+### File Format Version 2
+
+- File Format Version: varint
+- Header: Letter without Data and Mac as byte block
+	- Signatures only announce the signers (Scheme and ID)
+- Chunks (repeated until the last chunk):
+	- Flags: varint
+		- 1: Last Chunk
+	- Data: byte block
+	- MAC: byte block
+- Signatures: Letter with only Signatures as byte block
+
+Every chunk holds 65536 bytes of plaintext, except for the last one, which may hold less.
+Chunks of suites with a MAC or an integrated cipher are authenticated independently and can be opened in any order, see LetterFile.
+Use Session.CloseStream to create and OpenStream or OpenLetterFile to open letters in this format.
 */
 
-// ErrIncompatibleFileFormatVersion is returned when an incompatible wire format is encountered.
+// ErrIncompatibleFileFormatVersion is returned when an incompatible file format is encountered.
 var ErrIncompatibleFileFormatVersion = errors.New("incompatible file format version")
 
 // ToFileFormat serializes the letter for storing it as a file.
@@ -48,6 +67,12 @@ func (letter *Letter) ToFileFormat() (*container.Container, error) {
 	return c, nil
 }
 
+// IsLetterStreamFormat returns whether the data is a letter in File Format Version 2, as written by Session.CloseStream.
+func IsLetterStreamFormat(data []byte) bool {
+	fileFormatVersion, n := binary.Uvarint(data)
+	return n > 0 && fileFormatVersion == 2
+}
+
 // LetterFromFileFormat parses a letter stored as a file.
 func LetterFromFileFormat(c *container.Container) (*Letter, error) {
 	letter := &Letter{}
@@ -57,7 +82,11 @@ func LetterFromFileFormat(c *container.Container) (*Letter, error) {
 	if err != nil {
 		return nil, err
 	}
-	if fileFormatVersion != 1 {
+	switch fileFormatVersion {
+	case 1:
+	case 2:
+		return nil, fmt.Errorf("%w: version 2 must be opened with OpenStream or OpenLetterFile", ErrIncompatibleFileFormatVersion)
+	default:
 		return nil, ErrIncompatibleFileFormatVersion
 	}
 
@@ -80,3 +109,67 @@ func LetterFromFileFormat(c *container.Container) (*Letter, error) {
 
 	return letter, nil
 }
+
+// letterFileReader reads from an io.ReaderAt and keeps track of the offset.
+type letterFileReader struct {
+	r      io.ReaderAt
+	offset int64
+}
+
+// Read implements io.Reader.
+func (lfr *letterFileReader) Read(p []byte) (n int, err error) {
+	n, err = lfr.r.ReadAt(p, lfr.offset)
+	lfr.offset += int64(n)
+	if n == len(p) && errors.Is(err, io.EOF) {
+		// reaching EOF is fine if we got everything
+		err = nil
+	}
+	return n, err
+}
+
+// ReadByte implements io.ByteReader.
+func (lfr *letterFileReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(lfr, b[:])
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// skipBlock skips a length prefixed byte block and returns its size.
+func (lfr *letterFileReader) skipBlock() (size int64, err error) {
+	blockSize, err := binary.ReadUvarint(lfr)
+	if err != nil {
+		return 0, err
+	}
+	if blockSize > streamMaxBlockSize {
+		return 0, fmt.Errorf("stream block exceeds maximum size: %d", blockSize)
+	}
+
+	lfr.offset += int64(blockSize)
+	return int64(blockSize), nil
+}
+
+// skipChunk skips a chunk and returns the size of its data and whether it was the last one.
+func (lfr *letterFileReader) skipChunk() (dataSize int64, last bool, err error) {
+	// Flags: varint
+	flags, err := binary.ReadUvarint(lfr)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Data: byte block
+	dataSize, err = lfr.skipBlock()
+	if err != nil {
+		return 0, false, err
+	}
+
+	// MAC: byte block
+	_, err = lfr.skipBlock()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return dataSize, flags&streamChunkFlagLast != 0, nil
+}
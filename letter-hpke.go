@@ -0,0 +1,120 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/jess/tools/hpke"
+)
+
+// HPKESeal is the raw HPKE (RFC 9180) output of a letter of the HPKE-X25519 and HPKE-AUTH-X25519 tools.
+// The data of the letter is encrypted for its single recipient with a single-shot HPKE encryption, using the info string hpke.Info and the associated data of the letter. See the tools/hpke package for all parameters.
+//
+// In the letter, the encapsulated key is the value of the recipient's seal and the ciphertext is the data:
+//
+//	Keys: [{Scheme: "HPKE-AUTH-X25519", ID: <sender ID>}] (auth mode only)
+//	      {ID: <recipient ID>, Value: <encapsulated key>}
+//	Data: <ciphertext>
+//
+// The letter has no nonce, MAC or signatures and does not support padding or compression.
+type HPKESeal struct {
+	// RecipientID is the ID of the recipient's signet.
+	RecipientID string
+	// SenderID is the ID of the sender's signet in the auth mode.
+	SenderID string
+	// EncapsulatedKey is the encapsulated key, called "enc" in RFC 9180.
+	EncapsulatedKey []byte
+	// Ciphertext is the HPKE encrypted data of the letter, called "ct" in RFC 9180.
+	Ciphertext []byte
+}
+
+// ExportHPKESeal returns the raw HPKE output of the letter.
+func (letter *Letter) ExportHPKESeal() (*HPKESeal, error) {
+	tool, err := getHPKETool(letter.SuiteID)
+	if err != nil {
+		return nil, err
+	}
+
+	hpkeSeal := &HPKESeal{
+		Ciphertext: letter.Data,
+	}
+
+	// get sender reference
+	sealIndex := 0
+	if tool.Info.HasOption(tools.OptionAuthenticatesSender) {
+		if len(letter.Keys) == 0 || letter.Keys[0].Scheme != tool.Info.Name {
+			return nil, fmt.Errorf("missing sender reference for %s in letter", tool.Info.Name)
+		}
+		hpkeSeal.SenderID = letter.Keys[0].ID
+		sealIndex++
+	}
+
+	// get recipient seal
+	if len(letter.Keys) != sealIndex+1 {
+		return nil, errors.New("letter must have exactly one seal for the recipient")
+	}
+	hpkeSeal.RecipientID = letter.Keys[sealIndex].ID
+	hpkeSeal.EncapsulatedKey = letter.Keys[sealIndex].Value
+
+	return hpkeSeal, nil
+}
+
+// LetterFromHPKESeal creates a letter of the given suite from raw HPKE output, for example when it was produced by another HPKE implementation.
+func LetterFromHPKESeal(suiteID string, hpkeSeal *HPKESeal) (*Letter, error) {
+	tool, err := getHPKETool(suiteID)
+	if err != nil {
+		return nil, err
+	}
+	authMode := tool.Info.HasOption(tools.OptionAuthenticatesSender)
+
+	// check seal
+	switch {
+	case hpkeSeal.RecipientID == "":
+		return nil, errors.New("missing recipient ID")
+	case authMode && hpkeSeal.SenderID == "":
+		return nil, fmt.Errorf("missing sender ID for %s", tool.Info.Name)
+	case !authMode && hpkeSeal.SenderID != "":
+		return nil, fmt.Errorf("%s does not authenticate the sender", tool.Info.Name)
+	case len(hpkeSeal.EncapsulatedKey) != hpke.EncapsulatedKeySize:
+		return nil, fmt.Errorf("encapsulated key must be %d bytes", hpke.EncapsulatedKeySize)
+	case len(hpkeSeal.Ciphertext) == 0:
+		return nil, errors.New("missing ciphertext")
+	}
+
+	letter := &Letter{
+		Version: 1,
+		SuiteID: suiteID,
+		Data:    hpkeSeal.Ciphertext,
+	}
+	if authMode {
+		letter.Keys = append(letter.Keys, &Seal{
+			Scheme: tool.Info.Name,
+			ID:     hpkeSeal.SenderID,
+		})
+	}
+	letter.Keys = append(letter.Keys, &Seal{
+		ID:    hpkeSeal.RecipientID,
+		Value: hpkeSeal.EncapsulatedKey,
+	})
+
+	return letter, nil
+}
+
+// getHPKETool returns the HPKE tool of the given suite, which must be its only tool.
+func getHPKETool(suiteID string) (*tools.Tool, error) {
+	suite, ok := GetSuite(suiteID)
+	if !ok {
+		return nil, fmt.Errorf("suite %s does not exist", suiteID)
+	}
+	if len(suite.Tools) != 1 {
+		return nil, fmt.Errorf("suite %s does not use HPKE", suite.ID)
+	}
+
+	switch suite.Tools[0] {
+	case "HPKE-X25519", "HPKE-AUTH-X25519":
+		return tools.Get(suite.Tools[0])
+	default:
+		return nil, fmt.Errorf("suite %s does not use HPKE", suite.ID)
+	}
+}
@@ -0,0 +1,138 @@
+package jess
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/hpke"
+	"github.com/cloudflare/circl/kem"
+)
+
+func TestHPKEInterop(t *testing.T) {
+	t.Parallel()
+
+	hpkeSuite := hpke.NewSuite(hpke.KEM_X25519_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305)
+	info := []byte("jess")
+	ad := []byte("record-1")
+
+	for _, suiteID := range []string{SuiteRcptOnlyHPKEV1, SuiteAuthHPKEV1} {
+		suite := getSuite(t, suiteID)
+		envelope, err := setupEnvelopeAndTrustStore(t, suite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipient, err := testTrustStore.GetSignet(envelope.Recipients[0].ID, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipientKey, ok := recipient.PrivateKey().(kem.PrivateKey)
+		if !ok {
+			t.Fatal("unexpected private key type")
+		}
+		var senderKey kem.PrivateKey
+		if len(envelope.Senders) > 0 {
+			sender, err := testTrustStore.GetSignet(envelope.Senders[0].ID, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			senderKey, ok = sender.PrivateKey().(kem.PrivateKey)
+			if !ok {
+				t.Fatal("unexpected private key type")
+			}
+		}
+
+		// close with jess
+		s, err := envelope.Correspondence(testTrustStore)
+		if err != nil {
+			t.Fatal(err)
+		}
+		letter, err := s.CloseWithAD([]byte(testData1), ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// open with HPKE directly: the recipient's seal holds enc, the data is ct
+		enc := letter.Keys[len(letter.Keys)-1].Value
+		receiver, err := hpkeSuite.NewReceiver(recipientKey, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var opener hpke.Opener
+		if senderKey != nil {
+			opener, err = receiver.SetupAuth(enc, senderKey.Public())
+		} else {
+			opener, err = receiver.Setup(enc)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := opener.Open(letter.Data, ad)
+		if err != nil {
+			t.Fatalf("%s: failed to open letter with HPKE: %s", suiteID, err)
+		}
+		if string(data) != testData1 {
+			t.Fatalf("%s: data opened with HPKE does not match", suiteID)
+		}
+
+		// export
+		hpkeSeal, err := letter.ExportHPKESeal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hpkeSeal.RecipientID != recipient.ID ||
+			(senderKey != nil) != (hpkeSeal.SenderID != "") ||
+			!bytes.Equal(hpkeSeal.EncapsulatedKey, enc) ||
+			!bytes.Equal(hpkeSeal.Ciphertext, letter.Data) {
+			t.Fatalf("%s: unexpected HPKE seal: %+v", suiteID, hpkeSeal)
+		}
+
+		// close with HPKE directly, open with jess
+		sender, err := hpkeSuite.NewSender(recipientKey.Public(), info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sealer hpke.Sealer
+		if senderKey != nil {
+			enc, sealer, err = sender.SetupAuth(Random(), senderKey)
+		} else {
+			enc, sealer, err = sender.Setup(Random())
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := sealer.Seal([]byte(testData1), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		imported, err := LetterFromHPKESeal(suiteID, &HPKESeal{
+			RecipientID:     recipient.ID,
+			SenderID:        hpkeSeal.SenderID,
+			EncapsulatedKey: enc,
+			Ciphertext:      ct,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err = imported.Open(suite.Provides, testTrustStore)
+		if err != nil {
+			t.Fatalf("%s: failed to open HPKE output with jess: %s", suiteID, err)
+		}
+		if string(data) != testData1 {
+			t.Fatalf("%s: data opened with jess does not match", suiteID)
+		}
+
+		// streaming is not supported
+		_, err = s.CloseStream(&bytes.Buffer{}, bytes.NewReader([]byte(testData1)))
+		if err == nil {
+			t.Fatalf("%s: streaming should fail", suiteID)
+		}
+
+		// modified data must fail
+		ct[0] ^= 0x01
+		_, err = imported.Open(suite.Provides, testTrustStore)
+		if !errors.Is(err, ErrIntegrityViolation) {
+			t.Fatalf("%s: modified ciphertext should fail with an integrity violation, got: %v", suiteID, err)
+		}
+	}
+}
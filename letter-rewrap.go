@@ -0,0 +1,238 @@
+package jess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Rewrap returns a copy of the letter for the given recipients.
+//
+// If the letter has a wrapped key (see Envelope.WrapKey), the data key is unwrapped with the opening envelope and wrapped again for the new recipients with the same suite. Only the key seals and the wrapped key are replaced, the data is not touched.
+// Letters without a wrapped key are opened and closed again for the new recipients with the same suite, which re-encrypts the data with a new, wrapped key, if the suite supports it. Secrets, authenticated senders and compression are kept. Authenticating senders are loaded as private signets from the trust store, as the opening envelope only holds their public signets. A padded letter is padded to its original size again, so that the new letter hides the length of the data exactly like the original. The plaintext stays within this function.
+//
+// Signed letters cannot be rewrapped, as the signatures cover the seals.
+func (letter *Letter) Rewrap(openEnvelope *Envelope, newRecipients []*Signet, trustStore TrustStore) (*Letter, error) {
+	return letter.RewrapWithAD(openEnvelope, newRecipients, trustStore, nil)
+}
+
+// RewrapWithAD is like Rewrap, but for letters closed with associated data.
+// The associated data is only required for letters without a wrapped key, as they must be opened, and is bound to the new letter as well.
+// See Session.CloseWithAD for details.
+func (letter *Letter) RewrapWithAD(openEnvelope *Envelope, newRecipients []*Signet, trustStore TrustStore, ad []byte) (*Letter, error) {
+	err := letter.checkRewrap(openEnvelope, newRecipients)
+	if err != nil {
+		return nil, err
+	}
+
+	// replace the key seals only, if possible
+	if len(letter.WrappedKey) > 0 {
+		newLetter, err := letter.rewrapKey(openEnvelope, newRecipients, trustStore)
+		if err != nil {
+			return nil, err
+		}
+
+		// copy data, as it is decrypted in place when opening
+		newLetter.Data = make([]byte, len(letter.Data))
+		copy(newLetter.Data, letter.Data)
+		return newLetter, nil
+	}
+
+	// open a copy, as the data is decrypted in place
+	s, err := openEnvelope.Correspondence(trustStore)
+	if err != nil {
+		return nil, err
+	}
+	letterCopy := *letter
+	letterCopy.Data = make([]byte, len(letter.Data))
+	copy(letterCopy.Data, letter.Data)
+	paddedData, err := s.openLetter(&letterCopy, ad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open letter: %w", err)
+	}
+	data, err := s.unwrapLetterData(&letterCopy, paddedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open letter: %w", err)
+	}
+
+	// close
+	s, err = letter.rewrapSession(openEnvelope, newRecipients, trustStore)
+	if err != nil {
+		return nil, err
+	}
+	if letter.Compression != "" {
+		s.compression = letter.Compression
+	}
+	if letter.Padded {
+		// The compressed data has the same size again, so padding to a single
+		// block of the original size restores the exact padded size.
+		s.padding = &paddingPolicy{scheme: PaddingBlock, size: len(paddedData)}
+	}
+	newLetter, err := s.CloseWithAD(data, ad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close letter for new recipients: %w", err)
+	}
+
+	return newLetter, nil
+}
+
+// RewrapStream reads a letter stream from r and writes it to w for the given recipients.
+// The opening envelope is built from the header of the stream.
+//
+// If the letter has a wrapped key, only the header is replaced and the chunks are copied as they are.
+// Otherwise, the stream is opened and closed again for the new recipients, which re-encrypts the data with a new, wrapped key, if the suite supports it.
+// The plaintext is only passed between the two streams in memory. If an error is returned, all data written to w must be discarded.
+// See Letter.Rewrap for details.
+func RewrapStream(w io.Writer, r io.Reader, newRecipients []*Signet, requirements *Requirements, trustStore TrustStore) (*Letter, error) {
+	reader := bufio.NewReader(r)
+	letter, err := readStreamHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+	openEnvelope, err := letter.Envelope(requirements)
+	if err != nil {
+		return nil, err
+	}
+	err = letter.checkRewrap(openEnvelope, newRecipients)
+	if err != nil {
+		return nil, err
+	}
+
+	// replace the header only, if possible
+	if len(letter.WrappedKey) > 0 {
+		newLetter, err := letter.rewrapKey(openEnvelope, newRecipients, trustStore)
+		if err != nil {
+			return nil, err
+		}
+		err = writeStreamHeader(w, newLetter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write stream header: %w", err)
+		}
+		_, err = io.Copy(w, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy chunks: %w", err)
+		}
+		return newLetter, nil
+	}
+
+	// re-encrypt
+	openSession, err := openEnvelope.Correspondence(trustStore)
+	if err != nil {
+		return nil, err
+	}
+	closeSession, err := letter.rewrapSession(openEnvelope, newRecipients, trustStore)
+	if err != nil {
+		return nil, err
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	openErr := make(chan error, 1)
+	go func() {
+		err := openSession.openStream(pipeWriter, reader, letter)
+		_ = pipeWriter.CloseWithError(err)
+		openErr <- err
+	}()
+	newLetter, err := closeSession.CloseStream(w, pipeReader)
+	_ = pipeReader.Close()
+	if err := <-openErr; err != nil {
+		return nil, fmt.Errorf("failed to open letter: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close letter for new recipients: %w", err)
+	}
+
+	return newLetter, nil
+}
+
+// checkRewrap checks if the letter can be rewrapped.
+func (letter *Letter) checkRewrap(openEnvelope *Envelope, newRecipients []*Signet) error {
+	switch {
+	case openEnvelope == nil:
+		return errors.New("missing envelope for opening the letter")
+	case len(newRecipients) == 0:
+		return errors.New("missing new recipients")
+	case len(letter.Signatures) > 0:
+		return errors.New("signed letters cannot be rewrapped, as the signatures cover the seals")
+	default:
+		return nil
+	}
+}
+
+// rewrapKey unwraps the data key of the letter and returns a copy of the letter without data, with the data key wrapped for the new recipients.
+func (letter *Letter) rewrapKey(openEnvelope *Envelope, newRecipients []*Signet, trustStore TrustStore) (*Letter, error) {
+	if letter.Version != 1 {
+		return nil, fmt.Errorf("unsupported letter version: %d", letter.Version)
+	}
+
+	// unwrap data key
+	s, err := openEnvelope.Correspondence(trustStore)
+	if err != nil {
+		return nil, err
+	}
+	if s.kdf == nil {
+		return nil, errors.New("wrapped key requires a key derivation tool")
+	}
+	keyMaterial, err := s.setupOpeningKeyMaterial(letter)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := s.wrapDataKey(letter, letter.WrappedKey, keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+	defer Burn(dataKey)
+
+	// wrap data key for new recipients
+	s, err = letter.rewrapSession(openEnvelope, newRecipients, trustStore)
+	if err != nil {
+		return nil, err
+	}
+	newLetter := *letter
+	newLetter.Keys = nil
+	newLetter.Data = nil
+	keyMaterial, err = s.setupClosingKeyMaterial(&newLetter)
+	if err != nil {
+		return nil, err
+	}
+	newLetter.WrappedKey, err = s.wrapDataKey(&newLetter, dataKey, keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newLetter, nil
+}
+
+// rewrapSession returns a session for closing the letter for the new recipients with the secrets and authenticating senders of the opening envelope.
+// The session wraps the data key, if the suite supports it.
+func (letter *Letter) rewrapSession(openEnvelope *Envelope, newRecipients []*Signet, trustStore TrustStore) (*Session, error) {
+	// get private signets of senders for closing
+	senders := make([]*Signet, 0, len(openEnvelope.Senders))
+	for _, sender := range openEnvelope.Senders {
+		if trustStore == nil {
+			return nil, fmt.Errorf("rewrapping requires the private signet of sender %s: no truststore provided", sender.ID)
+		}
+		privateSender, err := trustStore.GetSignet(sender.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("rewrapping requires the private signet of sender %s: %w", sender.ID, err)
+		}
+		senders = append(senders, privateSender)
+	}
+
+	// build envelope for closing
+	e := &Envelope{
+		Version:     letter.Version,
+		SuiteID:     letter.SuiteID,
+		Secrets:     openEnvelope.Secrets,
+		Senders:     senders,
+		Recipients:  newRecipients,
+		Padding:     PaddingNone,
+		Compression: CompressionNone,
+	}
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup session for new recipients: %w", err)
+	}
+	s.wrapKey = s.kdf != nil
+
+	return s, nil
+}
@@ -0,0 +1,126 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/safing/jess/tools"
+)
+
+// AddSignature counter-signs the already closed letter with the senders of the given envelope.
+// Only the senders of the envelope are used, the signatures are made with the signing tools of the suite of the letter.
+// Signatures cover the header, data and MAC of the letter, but not other signatures, so every signature can be verified independently.
+// Every counter-signature also covers the time it was made at, which is used to check the validity of its signer.
+// Opening or verifying the letter verifies all present signatures.
+func (letter *Letter) AddSignature(senderEnvelope *Envelope, trustStore TrustStore) error {
+	return letter.AddSignatureWithAD(senderEnvelope, trustStore, nil)
+}
+
+// AddSignatureWithAD is like AddSignature, but for letters closed with associated data.
+// The counter-signatures cover the given associated data, which must match the associated data the letter was closed with.
+// See Session.CloseWithAD for details.
+func (letter *Letter) AddSignatureWithAD(senderEnvelope *Envelope, trustStore TrustStore, ad []byte) error {
+	if senderEnvelope == nil || len(senderEnvelope.Senders) == 0 {
+		return errors.New("missing senders for signing")
+	}
+
+	// create signing session
+	e, err := letter.signingEnvelope("")
+	if err != nil {
+		return err
+	}
+	e.Senders = append([]*Signet(nil), senderEnvelope.Senders...)
+	err = e.PrepareSignets(trustStore)
+	if err != nil {
+		return err
+	}
+	s, err := newSession(e)
+	if err != nil {
+		return err
+	}
+
+	// check for existing signatures
+	for _, sender := range e.Senders {
+		for _, seal := range letter.Signatures {
+			if sender.ID != "" && sender.ID == seal.ID {
+				return fmt.Errorf("letter is already signed by %s", sender.ID)
+			}
+		}
+	}
+
+	// sign, including the signing time of the counter-signature
+	letter.associatedData = ad
+	signedAt := clock().Unix()
+	associatedSigningData := (&Seal{SignedAt: signedAt}).compileAssociatedSigningData(
+		letter.compileAssociatedSigningData(nil),
+	)
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedHashers(s.managedSigningHashers, letter.Data, associatedSigningData)
+		if err != nil {
+			return err
+		}
+
+		defer s.resetManagedHashers(s.managedSigningHashers)
+	}
+	signed := &Letter{}
+	err = s.signLetter(signed, letter.Data, associatedSigningData)
+	if err != nil {
+		return err
+	}
+	for _, seal := range signed.Signatures {
+		seal.SignedAt = signedAt
+	}
+
+	// Add signatures and keep them ordered by signing tool, as expected when verifying.
+	toolOrder := make(map[string]int, len(s.signers))
+	for i, tool := range s.signers {
+		toolOrder[tool.Info().Name] = i
+	}
+	letter.Signatures = append(letter.Signatures, signed.Signatures...)
+	sort.SliceStable(letter.Signatures, func(i, j int) bool {
+		return toolOrder[letter.Signatures[i].Scheme] < toolOrder[letter.Signatures[j].Scheme]
+	})
+
+	return nil
+}
+
+// signingEnvelope returns an envelope with a suite that only holds the signing tools of the suite of the letter.
+// If a scheme is given, only the signing tool of that scheme is used.
+func (letter *Letter) signingEnvelope(scheme string) (*Envelope, error) {
+	suite, ok := GetSuite(letter.SuiteID)
+	if !ok {
+		return nil, fmt.Errorf("suite %s does not exist", letter.SuiteID)
+	}
+
+	var signingTools []string
+	for _, toolID := range suite.Tools {
+		tool, err := tools.Get(strings.Split(toolID, "(")[0])
+		if err != nil {
+			return nil, fmt.Errorf("the specified tool %s could not be found", toolID)
+		}
+		if tool.Info.Purpose == tools.PurposeSigning &&
+			(scheme == "" || scheme == tool.Info.Name) {
+			signingTools = append(signingTools, toolID)
+		}
+	}
+	if len(signingTools) == 0 {
+		if scheme != "" {
+			return nil, fmt.Errorf("suite %s does not support %s signatures", suite.ID, scheme)
+		}
+		return nil, fmt.Errorf("suite %s does not support signatures", suite.ID)
+	}
+
+	return &Envelope{
+		Version: letter.Version,
+		SuiteID: letter.SuiteID,
+		suite: &Suite{
+			ID:            suite.ID,
+			Tools:         signingTools,
+			Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+			SecurityLevel: suite.SecurityLevel,
+			Status:        suite.Status,
+		},
+	}, nil
+}
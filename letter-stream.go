@@ -0,0 +1,243 @@
+package jess
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/safing/structures/container"
+	"github.com/safing/structures/dsd"
+)
+
+// Letter streams are written in File Format Version 2, see letter-file.go.
+
+const (
+	// streamChunkSize defines the amount of plaintext that is processed per chunk.
+	// All chunks but the last one must hold exactly this amount of plaintext.
+	streamChunkSize = 65536
+
+	// streamMaxBlockSize defines the maximum size of a block that is accepted when reading a stream.
+	streamMaxBlockSize = 16777216 // 16MB
+
+	streamChunkFlagLast uint64 = 1
+)
+
+// streamReader is the reader interface needed to read letter streams.
+type streamReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// writeStreamHeader writes the file format version and the letter header to the writer.
+func writeStreamHeader(w io.Writer, letter *Letter) error {
+	c := container.New()
+
+	// File Format Version: varint
+	c.AppendNumber(2)
+
+	// Header: Letter without Data and Mac as byte block
+	headerData, err := dsd.DumpIndent(letter, dsd.JSON, "\t")
+	if err != nil {
+		return err
+	}
+	// add newline for better raw viewability
+	headerData = append(headerData, byte('\n'))
+	c.AppendAsBlock(headerData)
+
+	return c.WriteAllTo(w)
+}
+
+// readStreamHeader reads the file format version and the letter header from the reader.
+func readStreamHeader(r streamReader) (*Letter, error) {
+	// File Format Version: varint
+	fileFormatVersion, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if fileFormatVersion != 2 {
+		return nil, ErrIncompatibleFileFormatVersion
+	}
+
+	// Header: Letter without Data and Mac as byte block
+	headerData, err := readStreamBlock(r)
+	if err != nil {
+		return nil, err
+	}
+	letter := &Letter{}
+	_, err = dsd.Load(headerData, letter)
+	if err != nil {
+		return nil, err
+	}
+
+	// sanity check
+	if len(letter.Data) > 0 || len(letter.Mac) > 0 || letter.Padded || letter.Compression != "" {
+		return nil, errors.New("stream header may not contain data, mac, padding or compression")
+	}
+	for _, seal := range letter.Signatures {
+		if len(seal.Value) > 0 {
+			return nil, errors.New("stream header may only announce signatures")
+		}
+	}
+
+	return letter, nil
+}
+
+// writeStreamChunk writes a chunk to the writer.
+func writeStreamChunk(w io.Writer, data, mac []byte, last bool) error {
+	c := container.New()
+
+	// Flags: varint
+	var flags uint64
+	if last {
+		flags |= streamChunkFlagLast
+	}
+	c.AppendNumber(flags)
+
+	// Data: byte block
+	c.AppendAsBlock(data)
+
+	// MAC: byte block
+	c.AppendAsBlock(mac)
+
+	return c.WriteAllTo(w)
+}
+
+// readStreamChunk reads a chunk from the reader.
+func readStreamChunk(r streamReader) (data, mac []byte, last bool, err error) {
+	// Flags: varint
+	flags, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	last = flags&streamChunkFlagLast != 0
+
+	// Data: byte block
+	data, err = readStreamBlock(r)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// MAC: byte block
+	mac, err = readStreamBlock(r)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return data, mac, last, nil
+}
+
+// writeStreamSignatures writes the signatures of the letter to the writer.
+func writeStreamSignatures(w io.Writer, signatures []*Seal) error {
+	c := container.New()
+
+	// Signatures: Letter with only Signatures as byte block
+	sigData, err := dsd.Dump(&Letter{Signatures: signatures}, dsd.JSON)
+	if err != nil {
+		return err
+	}
+	c.AppendAsBlock(sigData)
+
+	return c.WriteAllTo(w)
+}
+
+// readStreamSignatures reads the signatures of the letter from the reader.
+// The signatures must be the end of the stream.
+func readStreamSignatures(r streamReader) ([]*Seal, error) {
+	// Signatures: Letter with only Signatures as byte block
+	sigData, err := readStreamBlock(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// check for trailing data
+	_, err = r.ReadByte()
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("%w: unexpected data after signatures", ErrIntegrityViolation)
+	case !errors.Is(err, io.EOF):
+		return nil, err
+	}
+
+	sigLetter := &Letter{}
+	_, err = dsd.Load(sigData, sigLetter)
+	if err != nil {
+		return nil, err
+	}
+
+	return sigLetter.Signatures, nil
+}
+
+// readStreamBlock reads a length prefixed byte block from the reader.
+func readStreamBlock(r streamReader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > streamMaxBlockSize {
+		return nil, fmt.Errorf("stream block exceeds maximum size: %d", size)
+	}
+
+	block := make([]byte, size)
+	_, err = io.ReadFull(r, block)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// readStreamPlaintextChunk reads the next plaintext chunk from the reader and reports whether it is the last one.
+func readStreamPlaintextChunk(r *bufio.Reader) (data []byte, last bool, err error) {
+	data = make([]byte, streamChunkSize)
+	n, err := io.ReadFull(r, data)
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return data[:n], true, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	// check if there is more data
+	_, err = r.Peek(1)
+	switch {
+	case errors.Is(err, io.EOF):
+		return data, true, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	return data, false, nil
+}
+
+func compileAssociatedChunkData(associatedData []byte, index uint64, last bool) []byte {
+	c := container.New(associatedData)
+	c.AppendNumber(fieldIDStreamChunkIndex) // append field ID
+	c.AppendNumber(index)
+	if last {
+		c.AppendNumber(fieldIDStreamChunkLast) // append field ID
+	}
+
+	return c.CompileData()
+}
+
+func compileAssociatedChunkSigningData(associatedChunkData, mac []byte) []byte {
+	// return if there is no Mac
+	if len(mac) == 0 {
+		return associatedChunkData
+	}
+
+	// add Mac to associated data and return
+	c := container.New(associatedChunkData)
+	c.AppendNumber(fieldIDLetterMac) // append field ID
+	c.AppendAsBlock(mac)             // append field content with length
+
+	return c.CompileData()
+}
+
+func compileStreamChunkNonce(nonce []byte, index uint64) []byte {
+	c := container.New(nonce)
+	c.AppendNumber(index)
+
+	return c.CompileData()
+}
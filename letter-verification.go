@@ -0,0 +1,195 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VerificationReport lists the verification results of all signatures of a letter.
+type VerificationReport struct {
+	Signatures []*SignatureReport
+}
+
+// SignatureReport holds the verification result of a single signature.
+type SignatureReport struct {
+	Scheme string
+	ID     string
+
+	// KnownSigner is true if the signet of the signer was found in the trust store.
+	// Signatures of unknown signers are ignored.
+	KnownSigner bool
+	// Verified is true if the signature was successfully verified.
+	Verified bool
+	// Info holds information about the signer, if available.
+	Info *SignetInfo
+	// Error holds the reason why the signature could not be verified.
+	Error error
+}
+
+// VerifiedSigners returns the IDs of all signers with a verified signature.
+func (vr *VerificationReport) VerifiedSigners() []string {
+	signers := make([]string, 0, len(vr.Signatures))
+	for _, sig := range vr.Signatures {
+		if sig.Verified {
+			signers = append(signers, sig.ID)
+		}
+	}
+	return signers
+}
+
+// OpenDetailed is like Open, but verifies every signature separately and reports the result for each of them.
+// Signatures of signers that are not in the trust store are ignored. Opening fails if a signature of a known signer is invalid, if no signature could be verified in a suite with signing tools or if the signature policy of the requirements is not satisfied.
+// The report is also returned if opening fails after the signatures were checked.
+func (letter *Letter) OpenDetailed(requirements *Requirements, trustStore TrustStore) ([]byte, *VerificationReport, error) {
+	return letter.openDetailed(requirements, trustStore, nil)
+}
+
+func (letter *Letter) openDetailed(requirements *Requirements, trustStore TrustStore, ad []byte) ([]byte, *VerificationReport, error) {
+	report, verifiedLetter, err := letter.verifyDetailed(requirements, trustStore, ad)
+	if err != nil {
+		return nil, report, err
+	}
+
+	data, err := verifiedLetter.open(requirements, trustStore, ad)
+	if err != nil {
+		return nil, report, err
+	}
+	return data, report, nil
+}
+
+// VerifyDetailed is like Verify, but verifies every signature separately and reports the result for each of them.
+// See OpenDetailed for details.
+func (letter *Letter) VerifyDetailed(requirements *Requirements, trustStore TrustStore) (*VerificationReport, error) {
+	return letter.verifyDetailedWithAD(requirements, trustStore, nil)
+}
+
+func (letter *Letter) verifyDetailedWithAD(requirements *Requirements, trustStore TrustStore, ad []byte) (*VerificationReport, error) {
+	// check requirements
+	if _, err := letter.Envelope(requirements); err != nil {
+		return nil, err
+	}
+
+	report, verifiedLetter, err := letter.verifyDetailed(requirements, trustStore, ad)
+	if err != nil {
+		return report, err
+	}
+	if len(verifiedLetter.Signatures) == 0 {
+		return report, errors.New("no signatures to verify")
+	}
+
+	// Verify the remaining signatures with the full suite, as when opening.
+	// This ensures that every signing tool of the suite has signed.
+	e, err := verifiedLetter.Envelope(requirements)
+	if err != nil {
+		return report, err
+	}
+	s, err := e.initCorrespondence(trustStore, true)
+	if err != nil {
+		return report, err
+	}
+	err = s.VerifyWithAD(verifiedLetter, ad)
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// verifyDetailed verifies all signatures separately and checks the signature policy of the requirements, if set.
+// It returns a report and a copy of the letter that only holds the verified signatures.
+func (letter *Letter) verifyDetailed(requirements *Requirements, trustStore TrustStore, ad []byte) (*VerificationReport, *Letter, error) {
+	letter.associatedData = ad
+
+	report := &VerificationReport{
+		Signatures: make([]*SignatureReport, 0, len(letter.Signatures)),
+	}
+	verifiedLetter := *letter
+	verifiedLetter.Signatures = nil
+
+	var failed []string
+	for _, seal := range letter.Signatures {
+		sigReport := &SignatureReport{
+			Scheme: seal.Scheme,
+			ID:     seal.ID,
+		}
+		report.Signatures = append(report.Signatures, sigReport)
+
+		// get signer from trust store
+		if trustStore == nil {
+			sigReport.Error = errors.New("no truststore provided")
+			continue
+		}
+		signet, err := trustStore.GetSignet(seal.ID, true)
+		if err != nil {
+			sigReport.Error = fmt.Errorf("signer not found: %w", err)
+			continue
+		}
+		sigReport.KnownSigner = true
+		sigReport.Info = signet.Info
+
+		// verify
+		err = letter.verifySignature(seal, signet, trustStore)
+		if err != nil {
+			sigReport.Error = err
+			failed = append(failed, seal.ID)
+			continue
+		}
+		sigReport.Verified = true
+		verifiedLetter.Signatures = append(verifiedLetter.Signatures, seal)
+	}
+
+	switch {
+	case len(failed) > 0:
+		return report, nil, fmt.Errorf("failed to verify signatures of %v", failed)
+	case len(letter.Signatures) > 0 && len(verifiedLetter.Signatures) == 0:
+		return report, nil, errors.New("no signature of a known signer")
+	}
+
+	// check signature policy
+	if requirements != nil && requirements.signaturePolicy != nil {
+		err := requirements.signaturePolicy.Check(report.VerifiedSigners())
+		if err != nil {
+			return report, nil, err
+		}
+	}
+
+	return report, &verifiedLetter, nil
+}
+
+// verifySignature verifies a single signature of the letter with the given signet.
+func (letter *Letter) verifySignature(seal *Seal, signet *Signet, trustStore TrustStore) error {
+	if signet.Scheme != seal.Scheme {
+		return fmt.Errorf("signer is of type %s, but signature is %s", signet.Scheme, seal.Scheme)
+	}
+
+	// create verification session
+	e, err := letter.signingEnvelope(seal.Scheme)
+	if err != nil {
+		return err
+	}
+	e.opening = true
+	e.Senders = []*Signet{signet}
+	err = e.PrepareSignets(trustStore)
+	if err != nil {
+		return err
+	}
+	s, err := newSession(e)
+	if err != nil {
+		return err
+	}
+
+	// verify
+	associatedSigningData := letter.compileAssociatedSigningData(nil)
+	if s.managedSigningHashers != nil {
+		err = s.feedManagedHashers(s.managedSigningHashers, letter.Data, associatedSigningData)
+		if err != nil {
+			return err
+		}
+
+		defer s.resetManagedHashers(s.managedSigningHashers)
+	}
+	return s.verifyLetterSignatures(&Letter{
+		SignedAt:   letter.SignedAt,
+		Signatures: []*Seal{seal},
+	}, letter.Data, associatedSigningData)
+}
@@ -1,7 +1,12 @@
 package jess
 
 import (
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"io"
+
+	"github.com/zeebo/blake3"
 
 	"github.com/safing/structures/container"
 )
@@ -14,6 +19,8 @@ import (
 	- 1: Setup Msg (includes Version and Tools)
 	- 2: Sending Keys
 	- 4: Apply Keys
+	- 8: Padded
+	- 16: Compressed (DEFLATE)
 - Version: varint (if Setup Msg)
 - SuiteID: byte block (if Setup Msg)
 - Keys:
@@ -22,22 +29,53 @@ import (
 - Nonce: byte block
 - Data: byte block
 - MAC: byte block
+
+### Wire Format Version 3 (concealed)
+
+- Conceal Salt: 16 random bytes, the first byte is never 1
+- Concealed: Wire Format Version 1 with version 3, XORed with a mask
+	- Mask: BLAKE3 XOF output of the Conceal Salt in key derivation mode
+
+Everything but the salt looks like random bytes to an observer, which hides
+the version, flags, suite ID and key seals from middleboxes.
+As the mask is derived from public data only, this does not provide any
+confidentiality on top of the used suite, similar to QUIC initial packet
+protection. The first byte of the salt is never 1 in order to distinguish
+concealed letters from Wire Format Version 1.
 */
 
 // ErrIncompatibleWireFormatVersion is returned when an incompatible wire format is encountered.
 var ErrIncompatibleWireFormatVersion = errors.New("incompatible wire format version")
 
+const (
+	wireFormatSimple    uint8 = 1
+	wireFormatConcealed uint8 = 3
+
+	wireConcealSaltSize = 16
+	wireConcealContext  = "safing.io/jess wire format v3 concealment"
+)
+
 // ToWire serializes to letter for sending it over a network connection.
+// Letters closed by a concealing wire session are serialized in the concealed wire format.
 func (letter *Letter) ToWire() (*container.Container, error) {
+	if letter.wireFormat == wireFormatConcealed {
+		return letter.toConcealedWire()
+	}
+	return letter.toWire(wireFormatSimple), nil
+}
+
+func (letter *Letter) toWire(wireFormatVersion uint8) *container.Container {
 	c := container.New()
 
 	// Wire Format Version: varint
-	c.AppendNumber(1)
+	c.AppendNumber(uint64(wireFormatVersion))
 
 	// Flags: varint
 	// 	 - 1: Setup Msg (includes Version and Tools)
 	// 	 - 2: Sending Keys
 	// 	 - 4: Apply Keys
+	// 	 - 8: Padded
+	// 	 - 16: Compressed (DEFLATE)
 	var flags uint64
 	if letter.Version > 0 {
 		flags |= 1
@@ -48,6 +86,12 @@ func (letter *Letter) ToWire() (*container.Container, error) {
 	if letter.ApplyKeys {
 		flags |= 4
 	}
+	if letter.Padded {
+		flags |= 8
+	}
+	if letter.Compression == CompressionDeflate {
+		flags |= 16
+	}
 	c.AppendNumber(flags)
 
 	if letter.Version > 0 {
@@ -81,7 +125,47 @@ func (letter *Letter) ToWire() (*container.Container, error) {
 	// debugging:
 	// fmt.Printf("%+v\n", c.CompileData())
 
-	return c, nil
+	return c
+}
+
+func (letter *Letter) toConcealedWire() (*container.Container, error) {
+	// Conceal Salt: 16 random bytes, the first byte is never 1
+	salt, err := RandomBytes(wireConcealSaltSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conceal salt: %w", err)
+	}
+	for salt[0] == wireFormatSimple {
+		_, err = io.ReadFull(Random(), salt[:1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get conceal salt: %w", err)
+		}
+	}
+
+	// Concealed: Wire Format Version 1 with version 3, XORed with a mask
+	data := letter.toWire(wireFormatConcealed).CompileData()
+	err = applyWireConcealMask(salt, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return container.New(salt, data), nil
+}
+
+func applyWireConcealMask(salt, data []byte) error {
+	h := blake3.NewDeriveKey(wireConcealContext)
+	_, err := h.Write(salt)
+	if err != nil {
+		return fmt.Errorf("failed to derive conceal mask: %w", err)
+	}
+
+	mask := make([]byte, len(data))
+	_, err = h.Digest().Read(mask)
+	if err != nil {
+		return fmt.Errorf("failed to derive conceal mask: %w", err)
+	}
+
+	subtle.XORBytes(data, data, mask)
+	return nil
 }
 
 // LetterFromWireData is a relay to LetterFromWire to quickly fix import issues of godep.
@@ -92,7 +176,43 @@ func LetterFromWireData(data []byte) (*Letter, error) {
 }
 
 // LetterFromWire parses a letter sent over a network connection.
+// Both the simple and the concealed wire format are detected automatically.
 func LetterFromWire(c *container.Container) (*Letter, error) {
+	if c.Length() == 0 {
+		return nil, errors.New("empty wire data")
+	}
+	if c.Peek(1)[0] != wireFormatSimple {
+		return letterFromConcealedWire(c)
+	}
+
+	return letterFromWire(c, wireFormatSimple)
+}
+
+func letterFromConcealedWire(c *container.Container) (*Letter, error) {
+	// Conceal Salt: 16 random bytes, the first byte is never 1
+	salt, err := c.Get(wireConcealSaltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Concealed: Wire Format Version 1 with version 3, XORed with a mask
+	// copy data in order to not modify the given container
+	data := make([]byte, c.Length())
+	copy(data, c.CompileData())
+	err = applyWireConcealMask(salt, data)
+	if err != nil {
+		return nil, err
+	}
+
+	letter, err := letterFromWire(container.New(data), wireFormatConcealed)
+	if err != nil {
+		return nil, err
+	}
+	letter.wireFormat = wireFormatConcealed
+	return letter, nil
+}
+
+func letterFromWire(c *container.Container, expectedWireFormatVersion uint8) (*Letter, error) {
 	letter := &Letter{}
 
 	// Wire Format Version: varint
@@ -100,7 +220,7 @@ func LetterFromWire(c *container.Container) (*Letter, error) {
 	if err != nil {
 		return nil, err
 	}
-	if wireFormatVersion != 1 {
+	if wireFormatVersion != expectedWireFormatVersion {
 		return nil, ErrIncompatibleWireFormatVersion
 	}
 
@@ -108,6 +228,8 @@ func LetterFromWire(c *container.Container) (*Letter, error) {
 	// 	 - 1: Setup Msg (includes Version and Tools)
 	// 	 - 2: Sending Keys
 	// 	 - 4: Apply Keys
+	// 	 - 8: Padded
+	// 	 - 16: Compressed (DEFLATE)
 	var (
 		setupMsg    bool
 		sendingKeys bool
@@ -125,6 +247,12 @@ func LetterFromWire(c *container.Container) (*Letter, error) {
 	if flags&4 > 0 {
 		letter.ApplyKeys = true
 	}
+	if flags&8 > 0 {
+		letter.Padded = true
+	}
+	if flags&16 > 0 {
+		letter.Compression = CompressionDeflate
+	}
 
 	if setupMsg {
 		// Version: varint (if Setup Msg)
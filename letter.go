@@ -2,13 +2,14 @@
 //
 // 1: for network, simple
 // 2: for storage
-// 3: for network, concealed (TBD)
+// 3: for network, concealed
 
 package jess
 
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/safing/structures/container"
 	"github.com/safing/structures/dsd"
@@ -20,14 +21,26 @@ type Letter struct { //nolint:maligned // TODO
 	SuiteID string // signed, MAC'd (may not exist when wired)
 
 	Nonce []byte  // signed, MAC'd
-	Keys  []*Seal `json:",omitempty"` // signed, MAC'd
+	Keys  []*Seal `json:",omitempty"` // signed, MAC'd (only signed if the key is wrapped)
 
-	Data       []byte  `json:",omitempty"` // signed, MAC'd
-	Mac        []byte  `json:",omitempty"` // signed
-	Signatures []*Seal `json:",omitempty"`
+	// WrappedKey is the random data key, encrypted with the key material of the key seals.
+	// Key seals of letters with a wrapped key can be replaced without re-encrypting the data, see Letter.Rewrap.
+	WrappedKey []byte `json:",omitempty"` // signed
+
+	Compression string  `json:",omitempty"` // signed, MAC'd
+	Padded      bool    `json:",omitempty"` // signed, MAC'd
+	SignedAt    int64   `json:",omitempty"` // signed, MAC'd (unix timestamp)
+	Data        []byte  `json:",omitempty"` // signed, MAC'd
+	Mac         []byte  `json:",omitempty"` // signed
+	Signatures  []*Seal `json:",omitempty"`
 
 	// Flags for wire protocol
 	ApplyKeys bool `json:",omitempty"` // MAC'd
+
+	// wireFormat is the wire format the letter was received in or should be sent in.
+	wireFormat uint8
+	// associatedData is additional data supplied by the caller. It is authenticated, but never transmitted.
+	associatedData []byte
 }
 
 // Seal holds a key, key exchange or signature within a letter.
@@ -41,6 +54,27 @@ type Seal struct {
 	// Key Establishment: Public key or wrapped key
 	// Signature: Signature value
 	Value []byte `json:",omitempty"`
+
+	// Signature: Time the signature was made at, if made after the letter was closed, ie. for counter-signatures.
+	SignedAt int64 `json:",omitempty"`
+}
+
+// isSenderReference returns whether the key seal references the sender that is authenticated by a tool that seals the payload, instead of holding key material.
+func (seal *Seal) isSenderReference() bool {
+	switch seal.Scheme {
+	case "", SignetSchemeKey, SignetSchemePassword:
+		return false
+	default:
+		return len(seal.Value) == 0
+	}
+}
+
+// signingTime returns the time the letter was signed at, or the current time if the letter does not specify it.
+func (letter *Letter) signingTime() time.Time {
+	if letter.SignedAt == 0 {
+		return clock()
+	}
+	return time.Unix(letter.SignedAt, 0)
 }
 
 // Envelope returns an envelope built from the letter, configured for opening it.
@@ -77,13 +111,20 @@ func (letter *Letter) Envelope(requirements *Requirements) (*Envelope, error) {
 	for _, seal := range letter.Keys {
 		// handshake messages have ephermal encapsulation keys in first message
 		if len(seal.ID) > 0 {
-			if seal.Scheme == SignetSchemeKey || seal.Scheme == SignetSchemePassword {
+			switch {
+			case seal.Scheme == SignetSchemeKey || seal.Scheme == SignetSchemePassword:
 				e.Secrets = append(e.Secrets, &Signet{
 					Version: letter.Version,
 					ID:      seal.ID,
 					Scheme:  seal.Scheme,
 				})
-			} else {
+			case seal.isSenderReference():
+				e.Senders = append(e.Senders, &Signet{
+					Version: letter.Version,
+					ID:      seal.ID,
+					Scheme:  seal.Scheme,
+				})
+			default:
 				e.Recipients = append(e.Recipients, &Signet{
 					Version: letter.Version,
 					ID:      seal.ID,
@@ -106,6 +147,21 @@ func (letter *Letter) Envelope(requirements *Requirements) (*Envelope, error) {
 
 // Open creates a session and opens the letter in one step.
 func (letter *Letter) Open(requirements *Requirements, trustStore TrustStore) ([]byte, error) {
+	return letter.OpenWithAD(requirements, trustStore, nil)
+}
+
+// OpenWithAD creates a session and opens the letter with the given associated data in one step.
+// See Session.CloseWithAD for details.
+func (letter *Letter) OpenWithAD(requirements *Requirements, trustStore TrustStore, ad []byte) ([]byte, error) {
+	if requirements != nil && requirements.signaturePolicy != nil {
+		data, _, err := letter.openDetailed(requirements, trustStore, ad)
+		return data, err
+	}
+
+	return letter.open(requirements, trustStore, ad)
+}
+
+func (letter *Letter) open(requirements *Requirements, trustStore TrustStore, ad []byte) ([]byte, error) {
 	e, err := letter.Envelope(requirements)
 	if err != nil {
 		return nil, err
@@ -116,11 +172,22 @@ func (letter *Letter) Open(requirements *Requirements, trustStore TrustStore) ([
 		return nil, err
 	}
 
-	return s.Open(letter)
+	return s.OpenWithAD(letter, ad)
 }
 
 // Verify creates a session and verifies the letter in one step.
 func (letter *Letter) Verify(requirements *Requirements, trustStore TrustStore) error {
+	return letter.VerifyWithAD(requirements, trustStore, nil)
+}
+
+// VerifyWithAD creates a session and verifies the letter with the given associated data in one step.
+// See Session.CloseWithAD for details.
+func (letter *Letter) VerifyWithAD(requirements *Requirements, trustStore TrustStore, ad []byte) error {
+	if requirements != nil && requirements.signaturePolicy != nil {
+		_, err := letter.verifyDetailedWithAD(requirements, trustStore, ad)
+		return err
+	}
+
 	e, err := letter.Envelope(requirements)
 	if err != nil {
 		return err
@@ -131,7 +198,7 @@ func (letter *Letter) Verify(requirements *Requirements, trustStore TrustStore)
 		return err
 	}
 
-	return s.Verify(letter)
+	return s.VerifyWithAD(letter, ad)
 }
 
 // WireCorrespondence creates a wire session (communication over a network connection) from a letter.
@@ -187,15 +254,27 @@ const (
 	// Field IDs for signing
 	// These IDs MUST NOT CHANGE.
 
-	fieldIDLetterVersion uint64 = 1 // signed, MAC'd (may not exist when wired)
-	fieldIDLetterSuiteID uint64 = 2 // signed, MAC'd (may not exist when wired)
-	fieldIDLetterNonce   uint64 = 3 // signed, MAC'd
-	fieldIDLetterKeys    uint64 = 4 // signed, MAC'd
-	fieldIDLetterMac     uint64 = 5 // signed
+	fieldIDLetterVersion     uint64 = 1 // signed, MAC'd (may not exist when wired)
+	fieldIDLetterSuiteID     uint64 = 2 // signed, MAC'd (may not exist when wired)
+	fieldIDLetterNonce       uint64 = 3 // signed, MAC'd
+	fieldIDLetterKeys        uint64 = 4 // signed, MAC'd
+	fieldIDLetterMac         uint64 = 5 // signed
+	fieldIDLetterPadded      uint64 = 6 // signed, MAC'd
+	fieldIDLetterCompression uint64 = 7 // signed, MAC'd
+
+	fieldIDLetterAssociatedData uint64 = 10 // signed, MAC'd (supplied by caller, not transmitted)
+	fieldIDLetterSignedAt       uint64 = 11 // signed, MAC'd
+	fieldIDLetterKeyWrapped     uint64 = 12 // signed, MAC'd
+	fieldIDLetterWrappedKey     uint64 = 13 // signed
+
+	fieldIDStreamChunkIndex uint64 = 8 // signed, MAC'd (only in streams)
+	fieldIDStreamChunkLast  uint64 = 9 // signed, MAC'd (only in streams)
 
 	fieldIDSealScheme uint64 = 16 // signed, MAC'd
 	fieldIDSealID     uint64 = 17 // signed, MAC'd
 	fieldIDSealValue  uint64 = 18 // signed, MAC'd
+
+	fieldIDSealSignedAt uint64 = 19 // signed (only in counter-signatures)
 )
 
 func (letter *Letter) compileAssociatedData() []byte {
@@ -216,13 +295,26 @@ func (letter *Letter) compileAssociatedData() []byte {
 		c.AppendNumber(fieldIDLetterNonce) // append field ID
 		c.AppendAsBlock(letter.Nonce)      // append field content with length
 	}
-	if len(letter.Keys) > 0 {
-		c.AppendNumber(fieldIDLetterKeys) // append field ID
-		c.AppendInt(len(letter.Keys))     // append number of keys
-		for i, seal := range letter.Keys {
-			c.AppendInt(i)                // append index
-			seal.compileAssociatedData(c) // append field content with length
-		}
+	if len(letter.WrappedKey) > 0 {
+		// Key seals and the wrapped key are only signed, so that they can be replaced.
+		c.AppendNumber(fieldIDLetterKeyWrapped) // append field ID
+	} else {
+		letter.compileAssociatedKeyData(c)
+	}
+	if letter.Padded {
+		c.AppendNumber(fieldIDLetterPadded) // append field ID
+	}
+	if len(letter.Compression) > 0 {
+		c.AppendNumber(fieldIDLetterCompression)    // append field ID
+		c.AppendAsBlock([]byte(letter.Compression)) // append field content with length
+	}
+	if letter.SignedAt != 0 {
+		c.AppendNumber(fieldIDLetterSignedAt)   // append field ID
+		c.AppendNumber(uint64(letter.SignedAt)) //nolint:gosec // append field content, negative values are fine
+	}
+	if len(letter.associatedData) > 0 {
+		c.AppendNumber(fieldIDLetterAssociatedData) // append field ID
+		c.AppendAsBlock(letter.associatedData)      // append field content with length
 	}
 
 	return c.CompileData()
@@ -234,19 +326,40 @@ func (letter *Letter) compileAssociatedSigningData(associatedData []byte) []byte
 		associatedData = letter.compileAssociatedData()
 	}
 
-	// return if there is no Mac
-	if len(letter.Mac) == 0 {
+	// return if there is nothing to add
+	if len(letter.WrappedKey) == 0 && len(letter.Mac) == 0 {
 		return associatedData
 	}
 
-	// add Mac to associated data and return
 	c := container.New(associatedData)
-	c.AppendNumber(fieldIDLetterMac) // append field ID
-	c.AppendAsBlock(letter.Mac)      // append field content with length
+
+	// add key seals and the wrapped key, as they are not part of the basic associated data
+	if len(letter.WrappedKey) > 0 {
+		letter.compileAssociatedKeyData(c)
+		c.AppendNumber(fieldIDLetterWrappedKey) // append field ID
+		c.AppendAsBlock(letter.WrappedKey)      // append field content with length
+	}
+
+	// add Mac
+	if len(letter.Mac) > 0 {
+		c.AppendNumber(fieldIDLetterMac) // append field ID
+		c.AppendAsBlock(letter.Mac)      // append field content with length
+	}
 
 	return c.CompileData()
 }
 
+func (letter *Letter) compileAssociatedKeyData(c *container.Container) {
+	if len(letter.Keys) > 0 {
+		c.AppendNumber(fieldIDLetterKeys) // append field ID
+		c.AppendInt(len(letter.Keys))     // append number of keys
+		for i, seal := range letter.Keys {
+			c.AppendInt(i)                // append index
+			seal.compileAssociatedData(c) // append field content with length
+		}
+	}
+}
+
 func (seal *Seal) compileAssociatedData(c *container.Container) {
 	if seal.Scheme != "" {
 		c.AppendNumber(fieldIDSealScheme)    // append field ID
@@ -261,3 +374,24 @@ func (seal *Seal) compileAssociatedData(c *container.Container) {
 		c.AppendAsBlock(seal.Value)      // append field content with length
 	}
 }
+
+// compileAssociatedSigningData adds the signing time of a counter-signature to the associated signing data of the letter.
+func (seal *Seal) compileAssociatedSigningData(associatedSigningData []byte) []byte {
+	if seal.SignedAt == 0 {
+		return associatedSigningData
+	}
+
+	c := container.New(associatedSigningData)
+	c.AppendNumber(fieldIDSealSignedAt)   // append field ID
+	c.AppendNumber(uint64(seal.SignedAt)) //nolint:gosec // append field content, negative values are fine
+
+	return c.CompileData()
+}
+
+// signingTime returns the time the signature was made at.
+func (seal *Seal) signingTime(letter *Letter) time.Time {
+	if seal.SignedAt != 0 {
+		return time.Unix(seal.SignedAt, 0)
+	}
+	return letter.signingTime()
+}
@@ -0,0 +1,161 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Padding Policies.
+// Policies with arguments are specified like tools, eg. "BLOCK(256)" or "BUCKETS(64,256,1024)".
+const (
+	// PaddingNone disables padding.
+	PaddingNone = "NONE"
+	// PaddingBlock pads data to a multiple of the given block size.
+	PaddingBlock = "BLOCK"
+	// PaddingPadme pads data as described in "Reducing Metadata Leakage from Encrypted Files and Communication with PURBs" by Nikitin et al., 2019.
+	// The overhead is at most 12% and decreases with bigger sizes.
+	PaddingPadme = "PADME"
+	// PaddingBuckets pads data to the next bucket size. Data bigger than the biggest bucket is padded to a multiple of it.
+	PaddingBuckets = "BUCKETS"
+)
+
+const (
+	// paddingMarker marks the start of the padding, which is followed by zeros only (ISO/IEC 7816-4).
+	paddingMarker byte = 0x80
+
+	maxPaddingSize = 16777216 // 16MB
+)
+
+// ErrInvalidPadding is returned when padding could not be removed from the data.
+var ErrInvalidPadding = errors.New("invalid padding")
+
+type paddingPolicy struct {
+	scheme  string
+	size    int
+	buckets []int
+}
+
+// parsePaddingPolicy parses the given padding policy. It returns nil if padding is disabled.
+func parsePaddingPolicy(policy string) (*paddingPolicy, error) {
+	if policy == "" {
+		return nil, nil
+	}
+
+	// split arguments
+	scheme, args, hasArgs := strings.Cut(policy, "(")
+	if hasArgs {
+		if !strings.HasSuffix(args, ")") {
+			return nil, fmt.Errorf("padding policy %s has invalid arguments", policy)
+		}
+		args = strings.TrimSuffix(args, ")")
+	}
+
+	// parse sizes
+	var sizes []int
+	if hasArgs {
+		for _, arg := range strings.Split(args, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil || size <= 0 || size > maxPaddingSize {
+				return nil, fmt.Errorf("padding policy %s has invalid size %q", policy, arg)
+			}
+			sizes = append(sizes, size)
+		}
+	}
+
+	switch scheme {
+	case PaddingNone:
+		if hasArgs {
+			return nil, fmt.Errorf("padding policy %s does not take arguments", scheme)
+		}
+		return nil, nil
+
+	case PaddingPadme:
+		if hasArgs {
+			return nil, fmt.Errorf("padding policy %s does not take arguments", scheme)
+		}
+		return &paddingPolicy{scheme: scheme}, nil
+
+	case PaddingBlock:
+		if len(sizes) != 1 {
+			return nil, fmt.Errorf("padding policy %s requires exactly one block size", scheme)
+		}
+		return &paddingPolicy{scheme: scheme, size: sizes[0]}, nil
+
+	case PaddingBuckets:
+		if len(sizes) == 0 {
+			return nil, fmt.Errorf("padding policy %s requires at least one bucket size", scheme)
+		}
+		for i := 1; i < len(sizes); i++ {
+			if sizes[i] <= sizes[i-1] {
+				return nil, fmt.Errorf("padding policy %s requires bucket sizes in ascending order", scheme)
+			}
+		}
+		return &paddingPolicy{scheme: scheme, buckets: sizes}, nil
+
+	default:
+		return nil, fmt.Errorf("padding policy %s does not exist", scheme)
+	}
+}
+
+// paddedSize returns the size that data of the given length is padded to, including the padding marker.
+func (pp *paddingPolicy) paddedSize(dataLength int) int {
+	// add padding marker
+	n := dataLength + 1
+
+	switch pp.scheme {
+	case PaddingBlock:
+		return roundUp(n, pp.size)
+
+	case PaddingPadme:
+		if n < 2 {
+			return n
+		}
+		e := bits.Len(uint(n)) - 1   // floor(log2(n))
+		s := bits.Len(uint(e))       // floor(log2(e)) + 1
+		mask := (1 << uint(e-s)) - 1 //nolint:gosec // e >= s
+		return (n + mask) &^ mask
+
+	case PaddingBuckets:
+		for _, bucket := range pp.buckets {
+			if n <= bucket {
+				return bucket
+			}
+		}
+		return roundUp(n, pp.buckets[len(pp.buckets)-1])
+
+	default:
+		return n
+	}
+}
+
+// pad returns a padded copy of the given data.
+func (pp *paddingPolicy) pad(data []byte) []byte {
+	padded := make([]byte, pp.paddedSize(len(data)))
+	copy(padded, data)
+	padded[len(data)] = paddingMarker
+	return padded
+}
+
+// unpad removes the padding from the given data.
+func unpad(data []byte) ([]byte, error) {
+	for i := len(data) - 1; i >= 0; i-- {
+		switch data[i] {
+		case 0:
+		case paddingMarker:
+			return data[:i], nil
+		default:
+			return nil, ErrInvalidPadding
+		}
+	}
+	return nil, ErrInvalidPadding
+}
+
+func roundUp(n, multiple int) int {
+	if remainder := n % multiple; remainder != 0 {
+		return n + multiple - remainder
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package jess
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPaddingPolicies(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		policy   string
+		sizes    map[int]int
+		disabled bool
+		invalid  bool
+	}{
+		{policy: "", disabled: true},
+		{policy: PaddingNone, disabled: true},
+		{policy: "BLOCK(16)", sizes: map[int]int{0: 16, 15: 16, 16: 32, 100: 112}},
+		{policy: "BUCKETS(64, 256,1024)", sizes: map[int]int{0: 64, 63: 64, 64: 256, 1000: 1024, 1024: 2048, 5000: 5120}},
+		{policy: PaddingPadme, sizes: map[int]int{0: 1, 1: 2, 8: 10, 99: 104, 1000: 1024, 9999: 10240}},
+		{policy: "BLOCK", invalid: true},
+		{policy: "BLOCK(0)", invalid: true},
+		{policy: "BLOCK(16", invalid: true},
+		{policy: "BUCKETS(256,64)", invalid: true},
+		{policy: "PADME(16)", invalid: true},
+		{policy: "NONE(16)", invalid: true},
+		{policy: "RANDOM", invalid: true},
+	} {
+		pp, err := parsePaddingPolicy(test.policy)
+		switch {
+		case test.invalid:
+			if err == nil {
+				t.Errorf("padding policy %q should be invalid", test.policy)
+			}
+			continue
+		case err != nil:
+			t.Errorf("failed to parse padding policy %q: %s", test.policy, err)
+			continue
+		case test.disabled:
+			if pp != nil {
+				t.Errorf("padding policy %q should disable padding", test.policy)
+			}
+			continue
+		}
+
+		for dataLength, expectedSize := range test.sizes {
+			if size := pp.paddedSize(dataLength); size != expectedSize {
+				t.Errorf("padding policy %q pads %d bytes to %d, expected %d", test.policy, dataLength, size, expectedSize)
+			}
+
+			data, err := RandomBytes(dataLength)
+			if err != nil {
+				t.Fatal(err)
+			}
+			unpadded, err := unpad(pp.pad(data))
+			if err != nil {
+				t.Errorf("padding policy %q failed to unpad %d bytes: %s", test.policy, dataLength, err)
+			} else if !bytes.Equal(data, unpadded) {
+				t.Errorf("padding policy %q corrupted %d bytes", test.policy, dataLength)
+			}
+		}
+	}
+
+	// invalid padding
+	for _, data := range [][]byte{nil, {0, 0}, {0x80, 1}} {
+		_, err := unpad(data)
+		if !errors.Is(err, ErrInvalidPadding) {
+			t.Errorf("padding %v should be invalid", data)
+		}
+	}
+}
+
+func TestPaddedLetters(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Padding = "BUCKETS(128,1024)"
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// letters within the same bucket must have the same size
+	letter1, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter2, err := s.Close([]byte(testData1[:10]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !letter1.Padded || len(letter1.Data) != len(letter2.Data) {
+		t.Fatalf("letters are not padded to the same size: %d != %d", len(letter1.Data), len(letter2.Data))
+	}
+
+	// open
+	data, err := letter1.Open(e.suite.Provides, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testData1 {
+		t.Fatalf("original data mismatch: %s", string(data))
+	}
+
+	// padding flag is authenticated
+	letter1.Padded = false
+	_, err = letter1.Open(e.suite.Provides, testTrustStore)
+	if !errors.Is(err, ErrIntegrityViolation) {
+		t.Fatalf("letter with modified padding flag should fail with an integrity violation, got: %v", err)
+	}
+
+	// padding requires confidentiality
+	e, err = setupEnvelopeAndTrustStore(t, getSuite(t, SuiteSign))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Padding = PaddingPadme
+	_, err = e.Correspondence(testTrustStore)
+	if err == nil {
+		t.Fatal("padding without confidentiality should fail")
+	}
+}
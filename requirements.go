@@ -16,6 +16,8 @@ const (
 // Requirements describe security properties.
 type Requirements struct {
 	all []uint8
+
+	signaturePolicy *SignaturePolicy
 }
 
 // newEmptyRequirements returns an empty requirements instance.
@@ -69,6 +71,26 @@ func (requirements *Requirements) Remove(attribute uint8) *Requirements {
 	return requirements
 }
 
+// SetSignaturePolicy sets a signature policy that is enforced when opening or verifying letters.
+// The signatures of letters are then verified separately and signatures of signers that are not in the trust store are ignored, see Letter.OpenDetailed.
+func (requirements *Requirements) SetSignaturePolicy(policy *SignaturePolicy) *Requirements {
+	requirements.signaturePolicy = policy
+	return requirements
+}
+
+// SignaturePolicy returns the signature policy, if set.
+func (requirements *Requirements) SignaturePolicy() *SignaturePolicy {
+	return requirements.signaturePolicy
+}
+
+// Copy returns a copy of the requirements.
+func (requirements *Requirements) Copy() *Requirements {
+	return &Requirements{
+		all:             append([]uint8(nil), requirements.all...),
+		signaturePolicy: requirements.signaturePolicy,
+	}
+}
+
 // CheckComplianceTo checks if the requirements are compliant to the given required requirements.
 func (requirements *Requirements) CheckComplianceTo(requirement *Requirements) error {
 	var missing *Requirements
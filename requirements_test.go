@@ -41,3 +41,33 @@ func TestRequirements(t *testing.T) {
 	a.Add(Confidentiality)
 	checkNoSpec(t, a, "")
 }
+
+func TestSignaturePolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		policy    *SignaturePolicy
+		verified  []string
+		satisfied bool
+	}{
+		{RequireAllSigners("a", "b"), []string{"a", "b", "c"}, true},
+		{RequireAllSigners("a", "b"), []string{"a", "c"}, false},
+		{RequireAnySigner("a", "b"), []string{"b"}, true},
+		{RequireAnySigner("a", "b"), []string{"c"}, false},
+		{RequireSigners(2, "a", "b", "c"), []string{"a", "c"}, true},
+		{RequireSigners(2, "a", "b", "c"), []string{"c", "d"}, false},
+		{RequireSigners(4, "a", "b", "c"), []string{"a", "b", "c"}, false},
+		{RequireAnySigner(), nil, false},
+		// duplicate signers are only counted once
+		{RequireSigners(2, "a", "a"), []string{"a"}, false},
+		{RequireSigners(2, "a", "a", "b"), []string{"a"}, false},
+		{RequireSigners(2, "a", "a", "b"), []string{"a", "b"}, true},
+		{RequireAllSigners("a", "a"), []string{"a"}, true},
+		{&SignaturePolicy{Signers: []string{"a", "a"}, Threshold: 2}, []string{"a"}, false},
+	} {
+		err := test.policy.Check(test.verified)
+		if (err == nil) != test.satisfied {
+			t.Errorf("policy %+v with verified signers %v: unexpected result %v", test.policy, test.verified, err)
+		}
+	}
+}
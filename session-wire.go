@@ -30,6 +30,7 @@ type WireSession struct { //nolint:maligned // TODO
 	session *Session
 
 	server           bool
+	concealed        bool
 	msgNo            uint64
 	lastReKeyAtMsgNo uint64
 
@@ -72,6 +73,10 @@ func (s *Session) initWireSession() error {
 			tools.PurposeSigning:
 			return fmt.Errorf("wire sessions currently do not support %s", tool.Info().Name)
 		}
+		if tool.Info().HasOption(tools.OptionAuthenticatesSender) ||
+			tool.Info().HasOption(tools.OptionSealsPayload) {
+			return fmt.Errorf("wire sessions currently do not support %s", tool.Info().Name)
+		}
 	}
 
 	// check for static pre shared keys
@@ -96,6 +101,14 @@ func (s *Session) Server() {
 	}
 }
 
+// ConcealWire makes a wire session send letters in the concealed wire format.
+// The peer automatically responds in the concealed wire format too.
+func (s *Session) ConcealWire() {
+	if s.wire != nil {
+		s.wire.concealed = true
+	}
+}
+
 // reKeyNeeded returns whether rekeying is needed.
 func (w *WireSession) reKeyNeeded() bool {
 	return w.msgNo-w.lastReKeyAtMsgNo > wireReKeyAfterMsgs
@@ -32,6 +32,7 @@ type Session struct {
 	passDerivator    tools.ToolLogic
 	keyExchangers    []tools.ToolLogic
 	keyEncapsulators []tools.ToolLogic
+	payloadSealer    tools.ToolLogic
 
 	integratedCiphers []tools.ToolLogic
 	ciphers           []tools.ToolLogic
@@ -41,6 +42,13 @@ type Session struct {
 
 	managedSigningHashers map[string]*managedHasher
 	signers               []tools.ToolLogic
+
+	padding *paddingPolicy
+
+	compression         string
+	maxDecompressedSize int
+
+	wrapKey bool
 }
 
 type managedHasher struct {
@@ -63,8 +71,9 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 
 	// create session
 	s := &Session{
-		envelope:         e,
-		toolRequirements: newEmptyRequirements(),
+		envelope:            e,
+		toolRequirements:    newEmptyRequirements(),
+		maxDecompressedSize: DefaultMaxDecompressedSize,
 	}
 
 	// check envelope security level
@@ -189,7 +198,7 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 			switch option {
 
 			case tools.OptionStreaming:
-				// TODO: Implementation pending.
+				// checked when streaming, see Session.CloseStream
 
 			case tools.OptionNeedsManagedHasher:
 				// get managed hasher list
@@ -240,6 +249,29 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 
 			case tools.OptionNeedsDefaultKeySize:
 				requireDefaultKeySize = true
+
+			case tools.OptionHasParameters:
+				err = logic.SetParameters(arg)
+				if err != nil {
+					return nil, fmt.Errorf("invalid parameters for %s(%s): %w", toolID, arg, err)
+				}
+
+			case tools.OptionAuthenticatesSender:
+				if !tool.Info.HasOption(tools.OptionSealsPayload) {
+					return nil, fmt.Errorf("only tools that seal the payload may authenticate the sender")
+				}
+				s.toolRequirements.Add(SenderAuthentication)
+
+			case tools.OptionSealsPayload:
+				if tool.Info.Purpose != tools.PurposeKeyEncapsulation {
+					return nil, fmt.Errorf("only key encapsulation tools may seal the payload")
+				}
+				if len(s.envelope.suite.Tools) != 1 {
+					return nil, fmt.Errorf("tool %s seals the payload itself and cannot be combined with other tools", tool.Info.Name)
+				}
+				s.payloadSealer = logic
+				s.toolRequirements.Add(Confidentiality)
+				s.toolRequirements.Add(Integrity)
 			}
 		}
 
@@ -306,6 +338,25 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 			})
 			keySourceAvailable = true
 
+			// check the authenticating sender
+			if err == nil && tool.Info().HasOption(tools.OptionAuthenticatesSender) {
+				var senders int
+				//nolint:scopelint // function is executed immediately within loop
+				err = e.LoopSenders(tool.Info().Name, func(signet *Signet) error {
+					senders++
+					return s.calcAndCheckSecurityLevel(tool, signet)
+				})
+				if err == nil && senders != 1 {
+					err = fmt.Errorf("tool %s requires exactly one sender", tool.Info().Name)
+				}
+				totalSignetsSeen += senders
+			}
+
+			// check the recipient of the sealed payload
+			if err == nil && seen != 1 && tool.Info().HasOption(tools.OptionSealsPayload) {
+				err = fmt.Errorf("tool %s requires exactly one recipient", tool.Info().Name)
+			}
+
 		case tools.PurposeSigning:
 			//nolint:scopelint // function is executed immediately within loop
 			err = e.LoopSenders(tool.Info().Name, func(signet *Signet) error {
@@ -391,7 +442,7 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 	}
 
 	// check if we are missing a kdf, but need one
-	if s.kdf == nil && len(s.signers) != len(s.envelope.suite.Tools) {
+	if s.kdf == nil && s.payloadSealer == nil && len(s.signers) != len(s.envelope.suite.Tools) {
 		return nil, errors.New("missing a key derivation tool")
 	}
 
@@ -409,6 +460,44 @@ func newSession(e *Envelope) (*Session, error) { //nolint:maintidx
 		return nil, errors.New("missing key source, please add a tool that provides a key or add a key signet directly")
 	}
 
+	// setup padding
+	paddingPolicy := s.envelope.Padding
+	if paddingPolicy == "" {
+		paddingPolicy = s.envelope.suite.Padding
+	}
+	s.padding, err = parsePaddingPolicy(paddingPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if s.padding != nil && !s.toolRequirements.Has(Confidentiality) {
+		return nil, errors.New("padding requires confidentiality")
+	}
+	if s.padding != nil && s.payloadSealer != nil {
+		return nil, fmt.Errorf("padding is not supported by %s", s.payloadSealer.Info().Name)
+	}
+
+	// setup compression
+	compression := s.envelope.Compression
+	if compression == "" {
+		compression = s.envelope.suite.Compression
+	}
+	s.compression, err = checkCompression(compression)
+	if err != nil {
+		return nil, err
+	}
+	if s.compression != "" && s.payloadSealer != nil {
+		return nil, fmt.Errorf("compression is not supported by %s", s.payloadSealer.Info().Name)
+	}
+
+	// setup key wrapping
+	s.wrapKey = s.envelope.WrapKey
+	if s.wrapKey && s.payloadSealer != nil {
+		return nil, fmt.Errorf("key wrapping is not supported by %s", s.payloadSealer.Info().Name)
+	}
+	if s.wrapKey && s.kdf == nil {
+		return nil, errors.New("key wrapping requires a key derivation tool")
+	}
+
 	// check if there are unused signets
 	if len(s.envelope.Secrets)+
 		len(s.envelope.Senders)+
@@ -446,16 +535,7 @@ func (s *Session) calcAndCheckSecurityLevel(logic tools.ToolLogic, signet *Signe
 		// only check if present
 		// existence check is done when opening/closing
 		if len(signet.Key) > 0 {
-			switch logic.Info().Name {
-			case "SCRYPT-20":
-				// TODO: integrate this into the tool interface
-				calculatedSecurityLevel = CalculatePasswordSecurityLevel(string(signet.Key), 1<<20)
-			case "PBKDF2-SHA2-256":
-				// TODO: integrate this into the tool interface
-				calculatedSecurityLevel = CalculatePasswordSecurityLevel(string(signet.Key), 20000)
-			default:
-				calculatedSecurityLevel = CalculatePasswordSecurityLevel(string(signet.Key), 1)
-			}
+			calculatedSecurityLevel = CalculatePasswordSecurityLevel(string(signet.Key), logic.PassDerivationCost())
 			if calculatedSecurityLevel < 0 {
 				return fmt.Errorf(`supplied password signet "%s" is exceptionally weak and should not be used`, signet.ID)
 			}
@@ -559,3 +639,23 @@ func (s *Session) NonceSize() int {
 
 	return size
 }
+
+// SetMaxDecompressedSize sets the maximum size of decompressed data when opening compressed letters.
+// Defaults to DefaultMaxDecompressedSize.
+func (s *Session) SetMaxDecompressedSize(size int) {
+	s.maxDecompressedSize = size
+}
+
+// signedAt returns the time to record as signing time of a letter, or zero if none is needed.
+// The signing time is only recorded if a sender has a validity period, as it is part of the signed data and older versions of jess do not know it.
+func (s *Session) signedAt() int64 {
+	if len(s.signers) == 0 {
+		return 0
+	}
+	for _, sender := range s.envelope.Senders {
+		if sender.Info != nil && (!sender.Info.NotBefore.IsZero() || !sender.Info.Expires.IsZero()) {
+			return clock().Unix()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSignaturePolicyNotSatisfied is returned when the verified signatures of a letter do not satisfy the signature policy.
+var ErrSignaturePolicyNotSatisfied = errors.New("signature policy not satisfied")
+
+// SignaturePolicy defines which signers must have signed a letter.
+// It is checked against the signatures that were actually verified, see Requirements.SetSignaturePolicy.
+type SignaturePolicy struct {
+	// Signers holds the IDs of the signets that may satisfy the policy.
+	// Duplicate IDs are only counted once.
+	Signers []string
+	// Threshold is the minimum number of signers that must have signed.
+	Threshold int
+}
+
+// RequireAllSigners returns a signature policy that requires signatures of all given signers.
+func RequireAllSigners(signerIDs ...string) *SignaturePolicy {
+	signerIDs = uniqueSigners(signerIDs)
+	return &SignaturePolicy{
+		Signers:   signerIDs,
+		Threshold: len(signerIDs),
+	}
+}
+
+// RequireAnySigner returns a signature policy that requires a signature of at least one of the given signers.
+func RequireAnySigner(signerIDs ...string) *SignaturePolicy {
+	return &SignaturePolicy{
+		Signers:   uniqueSigners(signerIDs),
+		Threshold: 1,
+	}
+}
+
+// RequireSigners returns a signature policy that requires signatures of at least threshold of the given signers.
+func RequireSigners(threshold int, signerIDs ...string) *SignaturePolicy {
+	return &SignaturePolicy{
+		Signers:   uniqueSigners(signerIDs),
+		Threshold: threshold,
+	}
+}
+
+// Check checks if the given verified signers satisfy the policy.
+// Signers that are not part of the policy are ignored.
+func (policy *SignaturePolicy) Check(verifiedSigners []string) error {
+	signers := uniqueSigners(policy.Signers)
+	if policy.Threshold <= 0 || policy.Threshold > len(signers) {
+		return fmt.Errorf("invalid signature policy: threshold of %d for %d signers", policy.Threshold, len(signers))
+	}
+
+	var satisfied int
+	for _, signer := range signers {
+		if stringInSlice(signer, verifiedSigners) {
+			satisfied++
+		}
+	}
+
+	if satisfied < policy.Threshold {
+		return fmt.Errorf(
+			"%w: %d of the required %d signatures of [%s] present",
+			ErrSignaturePolicyNotSatisfied,
+			satisfied,
+			policy.Threshold,
+			strings.Join(signers, ", "),
+		)
+	}
+	return nil
+}
+
+// uniqueSigners returns the signer IDs without duplicates, keeping their order.
+func uniqueSigners(signerIDs []string) []string {
+	unique := make([]string, 0, len(signerIDs))
+	for _, signerID := range signerIDs {
+		if !stringInSlice(signerID, unique) {
+			unique = append(unique, signerID)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,168 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/safing/structures/container"
+)
+
+// ErrSignetNotCertified is returned when verifying a signet that holds no certification.
+var ErrSignetNotCertified = errors.New("signet is not certified")
+
+// certificationAD is the associated data of certifications.
+// It separates certifications from all other signatures of the certifier, as it is not part of the data and must be supplied when signing.
+var certificationAD = []byte("jess signet certification v1")
+
+// Field IDs of the certified signet data.
+const (
+	fieldIDSignetVersion uint64 = 1
+	fieldIDSignetID      uint64 = 2
+	fieldIDSignetScheme  uint64 = 3
+	fieldIDSignetKey     uint64 = 4
+	fieldIDSignetPublic  uint64 = 5
+
+	fieldIDSignetInfoName      uint64 = 16
+	fieldIDSignetInfoOwner     uint64 = 17
+	fieldIDSignetInfoCreated   uint64 = 18
+	fieldIDSignetInfoExpires   uint64 = 19
+	fieldIDSignetInfoNotBefore uint64 = 20
+	fieldIDSignetInfoDetails   uint64 = 21
+)
+
+// Certify signs the Version, ID, Scheme, public Key and Info of the signet with the senders of the given envelope and stores the certification in the Signature field.
+// Certifications always cover the public version of the signet, so they stay valid when the signet is converted to a recipient.
+// The envelope must use a suite that only signs. If the signet is already certified, the new signatures are added to the existing certification using its suite.
+// Changing any of the certified fields invalidates the certification.
+// The signatures are bound to their purpose, so that other signatures of the certifier over the same data are not accepted as certifications.
+func (signet *Signet) Certify(signerEnvelope *Envelope, trustStore TrustStore) error {
+	data, err := signet.compileCertificationData()
+	if err != nil {
+		return err
+	}
+
+	// Add to existing certification.
+	if signet.Signature != nil {
+		certification := *signet.Signature
+		certification.Data = data
+		err = certification.AddSignatureWithAD(signerEnvelope, trustStore, certificationAD)
+		if err != nil {
+			return err
+		}
+		certification.Data = nil
+		signet.Signature = &certification
+		return nil
+	}
+
+	// Create new certification.
+	s, err := signerEnvelope.Correspondence(trustStore)
+	if err != nil {
+		return err
+	}
+	if s.kdf != nil || s.envelope.suite.Provides.Has(Confidentiality) {
+		return fmt.Errorf("suite %s cannot be used for certification, as it does not only sign", s.envelope.SuiteID)
+	}
+	certification, err := s.CloseWithAD(data, certificationAD)
+	if err != nil {
+		return err
+	}
+
+	// The certified data is compiled from the signet when verifying.
+	certification.Data = nil
+	signet.Signature = certification
+	return nil
+}
+
+// Verify verifies the certification of the signet with the given trust store.
+// Signatures of certifiers that are not in the trust store are ignored. Verification fails if a signature of a known certifier is invalid or if no signature of a known certifier is present.
+func (signet *Signet) Verify(trustStore TrustStore) error {
+	_, err := signet.VerifyDetailed(trustStore)
+	return err
+}
+
+// VerifyDetailed is like Verify, but also reports the verification result for every certifier.
+func (signet *Signet) VerifyDetailed(trustStore TrustStore) (*VerificationReport, error) {
+	if signet.Signature == nil {
+		return nil, ErrSignetNotCertified
+	}
+
+	data, err := signet.compileCertificationData()
+	if err != nil {
+		return nil, err
+	}
+
+	certification := *signet.Signature
+	certification.Data = data
+	report, err := certification.verifyDetailedWithAD(
+		newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		trustStore,
+		certificationAD,
+	)
+	if err != nil {
+		return report, fmt.Errorf("failed to verify certification of signet %s: %w", signet.ID, err)
+	}
+	return report, nil
+}
+
+// compileCertificationData compiles the certified data of the public version of the signet.
+func (signet *Signet) compileCertificationData() ([]byte, error) {
+	// Get public key in serialized form.
+	publicKey := signet.Key
+	if !signet.Public || len(publicKey) == 0 {
+		rcpt, err := signet.AsRecipient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get public version of signet: %w", err)
+		}
+		err = rcpt.StoreKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize public key: %w", err)
+		}
+		publicKey = rcpt.Key
+	}
+
+	c := container.New()
+
+	c.AppendNumber(fieldIDSignetVersion)
+	c.AppendNumber(uint64(signet.Version))
+	c.AppendNumber(fieldIDSignetID)
+	c.AppendAsBlock([]byte(signet.ID))
+	c.AppendNumber(fieldIDSignetScheme)
+	c.AppendAsBlock([]byte(signet.Scheme))
+	c.AppendNumber(fieldIDSignetKey)
+	c.AppendAsBlock(publicKey)
+	c.AppendNumber(fieldIDSignetPublic) // always public
+
+	if info := signet.Info; info != nil {
+		// Times are compiled with second precision, as this is what survives all serialization formats.
+		if info.Name != "" {
+			c.AppendNumber(fieldIDSignetInfoName)
+			c.AppendAsBlock([]byte(info.Name))
+		}
+		if info.Owner != "" {
+			c.AppendNumber(fieldIDSignetInfoOwner)
+			c.AppendAsBlock([]byte(info.Owner))
+		}
+		if !info.Created.IsZero() {
+			c.AppendNumber(fieldIDSignetInfoCreated)
+			c.AppendNumber(uint64(info.Created.Unix())) //nolint:gosec // negative values are fine
+		}
+		if !info.Expires.IsZero() {
+			c.AppendNumber(fieldIDSignetInfoExpires)
+			c.AppendNumber(uint64(info.Expires.Unix())) //nolint:gosec // negative values are fine
+		}
+		if !info.NotBefore.IsZero() {
+			c.AppendNumber(fieldIDSignetInfoNotBefore)
+			c.AppendNumber(uint64(info.NotBefore.Unix())) //nolint:gosec // negative values are fine
+		}
+		if len(info.Details) > 0 {
+			c.AppendNumber(fieldIDSignetInfoDetails)
+			c.AppendInt(len(info.Details))
+			for _, detail := range info.Details {
+				c.AppendAsBlock([]byte(detail[0]))
+				c.AppendAsBlock([]byte(detail[1]))
+			}
+		}
+	}
+
+	return c.CompileData(), nil
+}
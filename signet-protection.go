@@ -0,0 +1,131 @@
+package jess
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/safing/structures/dsd"
+)
+
+// Protect encrypts the key of the signet with the secrets of the given envelope, usually a password.
+// The key is then kept as a serialized letter and the envelope is stored in the Protection field for reference.
+// Passwords without a key are requested using the callbacks set with SetPasswordCallbacks.
+// Protected signets must be unprotected before use, which is done automatically when they are used in an envelope.
+func (signet *Signet) Protect(envelope *Envelope, trustStore TrustStore) error {
+	// check signet
+	switch {
+	case signet.Protection != nil:
+		return errors.New("signet is already protected")
+	case signet.Scheme == SignetSchemePassword:
+		return errors.New("passwords cannot be protected")
+	case len(envelope.Secrets) == 0:
+		return errors.New("protection requires at least one secret")
+	}
+
+	// serialize key
+	err := signet.StoreKey()
+	if err != nil {
+		return fmt.Errorf("failed to serialize key: %w", err)
+	}
+
+	// Copy the envelope, as preparing the signets modifies them.
+	e := *envelope
+	e.Secrets = make([]*Signet, 0, len(envelope.Secrets))
+	for _, secret := range envelope.Secrets {
+		secretCopy := *secret
+		// Secrets must be referenced in the letter in order to be found again.
+		if secretCopy.ID == "" {
+			err := secretCopy.AssignUUID()
+			if err != nil {
+				return err
+			}
+		}
+		e.Secrets = append(e.Secrets, &secretCopy)
+	}
+	e.Senders = nil
+	e.Recipients = nil
+
+	// create protection session
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		return err
+	}
+	if s.kdf == nil || !s.envelope.suite.Provides.Has(Confidentiality) {
+		return fmt.Errorf("suite %s cannot be used for protection, as it does not provide confidentiality", e.SuiteID)
+	}
+
+	// encrypt key
+	letter, err := s.Close(signet.Key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	protectedKey, err := letter.ToDSD(dsd.CBOR)
+	if err != nil {
+		return fmt.Errorf("failed to serialize protected key: %w", err)
+	}
+
+	// Reference the secrets, but remove all key material.
+	e.CleanSignets()
+	signet.Key = protectedKey
+	signet.Protection = &Envelope{
+		Version: e.Version,
+		SuiteID: e.SuiteID,
+		Secrets: e.Secrets,
+	}
+	signet.loadedPublicKey = nil
+	signet.loadedPrivateKey = nil
+
+	return nil
+}
+
+// Unprotect decrypts the key of a protected signet and removes the protection.
+// Passwords are requested using the callbacks set with SetPasswordCallbacks, keys are taken from the trust store.
+func (signet *Signet) Unprotect(trustStore TrustStore) error {
+	if signet.Protection == nil {
+		return nil
+	}
+
+	// load protected key
+	letter, err := LetterFromDSD(signet.Key)
+	if err != nil {
+		return fmt.Errorf("failed to parse protected key: %w", err)
+	}
+	e, err := letter.Envelope(newEmptyRequirements().Add(Confidentiality).Add(Integrity))
+	if err != nil {
+		return err
+	}
+
+	// Name secrets after the signet, so users know what they are asked for.
+	name := signet.ID
+	if signet.Info != nil && signet.Info.Name != "" {
+		name = signet.Info.Name
+	}
+	for _, secret := range e.Secrets {
+		if secret.Info == nil {
+			secret.Info = &SignetInfo{
+				Name: fmt.Sprintf("protection of %s", name),
+			}
+		}
+	}
+
+	// decrypt key
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		return err
+	}
+	key, err := s.Open(letter)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt key: %w", err)
+	}
+
+	signet.Key = key
+	signet.Protection = nil
+
+	// load key
+	switch signet.Scheme {
+	case SignetSchemeKey, SignetSchemePassword:
+		return nil
+	default:
+		return signet.LoadKey()
+	}
+}
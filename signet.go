@@ -33,7 +33,7 @@ type Signet struct { //nolint:maligned // TODO
 	// Metadata about Signet
 	Info *SignetInfo `json:",omitempty"`
 
-	// Signature of Version, Scheme, Key, Public, Protected, Info
+	// Certification of the public Version, ID, Scheme, Key and Info, see Certify.
 	Signature *Letter `json:",omitempty"`
 
 	// cache
@@ -48,10 +48,35 @@ type SignetInfo struct {
 	Owner   string
 	Created time.Time
 	Expires time.Time
+	// NotBefore is the time from which the signet may be used.
+	NotBefore time.Time
 
 	Details [][2]string
 }
 
+// Signet validity errors.
+var (
+	ErrSignetExpired     = errors.New("signet has expired")
+	ErrSignetNotYetValid = errors.New("signet is not yet valid")
+)
+
+// CheckValidity checks if the signet is valid at the given time, according to the NotBefore and Expires fields of its info.
+// Signets without info or without these fields are always valid.
+func (signet *Signet) CheckValidity(at time.Time) error {
+	if signet.Info == nil {
+		return nil
+	}
+
+	switch {
+	case !signet.Info.NotBefore.IsZero() && at.Before(signet.Info.NotBefore):
+		return fmt.Errorf("%w: %s is valid from %s", ErrSignetNotYetValid, signet.ID, signet.Info.NotBefore.Format(time.RFC3339))
+	case !signet.Info.Expires.IsZero() && !at.Before(signet.Info.Expires):
+		return fmt.Errorf("%w: %s expired at %s", ErrSignetExpired, signet.ID, signet.Info.Expires.Format(time.RFC3339))
+	default:
+		return nil
+	}
+}
+
 // NewSignetBase creates a new signet base without a key.
 func NewSignetBase(tool *tools.Tool) *Signet {
 	return &Signet{
@@ -158,7 +183,7 @@ func (signet *Signet) AsRecipient() (*Signet, error) {
 		Public:           true, // mark explicitly as public
 		Protection:       nil,  // remove protection
 		Info:             signet.Info,
-		Signature:        nil, // remove signature, as it would be invalid
+		Signature:        signet.Signature, // certifications cover the public version
 		tool:             signet.tool,
 		loadedPublicKey:  signet.loadedPublicKey,
 		loadedPrivateKey: nil, // remove private key
@@ -228,12 +253,6 @@ func (signet *Signet) StoreKey() error {
 	return signet.tool.StaticLogic.StoreKey(signet)
 }
 
-// Verify verifies the signature of the signet.
-func (signet *Signet) Verify() error {
-	// TODO
-	return errors.New("signet verification not yet implemented")
-}
-
 // Burn destroys all the key material and renders the Signet unusable. This is currently ineffective, see known issues in the project's README.
 func (signet *Signet) Burn() error {
 	// load tool
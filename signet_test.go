@@ -0,0 +1,456 @@
+package jess
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/safing/jess/tools"
+)
+
+func TestSignetValidity(t *testing.T) { //nolint:paralleltest // Modifies the global clock.
+	defer SetClock(nil)
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	setTestClock := func(ts time.Time) {
+		SetClock(func() time.Time { return ts })
+	}
+
+	// create signet that is valid for one year, starting one day after creation
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet := NewSignetBase(tool)
+	signet.ID = "test-signet-validity"
+	signet.Info = &SignetInfo{
+		Created:   created,
+		NotBefore: created.Add(24 * time.Hour),
+		Expires:   created.AddDate(1, 0, 0),
+	}
+	err = signet.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testTrustStore.StoreSignet(signet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcpt, err := signet.AsRecipient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testTrustStore.StoreSignet(rcpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closeLetter := func() (*Letter, error) {
+		e := &Envelope{
+			Version: 1,
+			SuiteID: SuiteSign,
+			Senders: []*Signet{{ID: signet.ID}},
+		}
+		s, err := e.Correspondence(testTrustStore)
+		if err != nil {
+			return nil, err
+		}
+		return s.Close([]byte(testData1))
+	}
+
+	// not yet valid
+	setTestClock(created)
+	_, err = closeLetter()
+	if !errors.Is(err, ErrSignetNotYetValid) {
+		t.Fatalf("closing with a signet that is not yet valid should fail, got: %v", err)
+	}
+
+	// valid
+	setTestClock(created.AddDate(0, 6, 0))
+	letter, err := closeLetter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if letter.SignedAt != created.AddDate(0, 6, 0).Unix() {
+		t.Fatalf("unexpected signing time: %d", letter.SignedAt)
+	}
+	msg, err := letter.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// expired
+	setTestClock(created.AddDate(2, 0, 0))
+	_, err = closeLetter()
+	if !errors.Is(err, ErrSignetExpired) {
+		t.Fatalf("closing with an expired signet should fail, got: %v", err)
+	}
+
+	// letters signed within the validity period still verify
+	letter, err = LetterFromJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err != nil {
+		t.Fatalf("letter signed while the signet was valid should verify: %s", err)
+	}
+
+	// the signing time is authenticated
+	letter.SignedAt = created.Unix()
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err == nil {
+		t.Fatal("letter with modified signing time should fail")
+	}
+
+	// letters without signing time are checked against the current time
+	letter.SignedAt = 0
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if !errors.Is(err, ErrSignetExpired) {
+		t.Fatalf("letter without signing time should be checked against the current time, got: %v", err)
+	}
+}
+
+func TestCounterSignatureValidity(t *testing.T) { //nolint:paralleltest // Modifies the global clock.
+	defer SetClock(nil)
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	setTestClock := func(ts time.Time) {
+		SetClock(func() time.Time { return ts })
+	}
+
+	// create co-signer that only becomes valid one month after the letter is signed
+	tool, err := tools.Get("Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	coSigner := NewSignetBase(tool)
+	coSigner.ID = "test-signet-co-signer-validity"
+	coSigner.Info = &SignetInfo{
+		Created:   created,
+		NotBefore: created.AddDate(0, 1, 0),
+		Expires:   created.AddDate(0, 2, 0),
+	}
+	err = coSigner.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testTrustStore.StoreSignet(coSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcpt, err := coSigner.AsRecipient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testTrustStore.StoreSignet(rcpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sign letter
+	setTestClock(created)
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteSign))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// counter-sign while the co-signer is valid
+	setTestClock(created.AddDate(0, 1, 1))
+	err = letter.AddSignature(&Envelope{Senders: []*Signet{{ID: coSigner.ID}}}, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := letter.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both signatures verify at their own signing time, even after the co-signer expired
+	setTestClock(created.AddDate(1, 0, 0))
+	letter, err = LetterFromJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The signer of the letter has no validity period, so its signing time is not recorded.
+	if letter.SignedAt != 0 || letter.Signatures[1].SignedAt != created.AddDate(0, 1, 1).Unix() {
+		t.Fatalf("unexpected signing times: %d, %d", letter.SignedAt, letter.Signatures[1].SignedAt)
+	}
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err != nil {
+		t.Fatalf("counter-signed letter should verify: %s", err)
+	}
+	_, err = letter.VerifyDetailed(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err != nil {
+		t.Fatalf("counter-signed letter should verify in detail: %s", err)
+	}
+
+	// the signing time of the counter-signature is authenticated
+	letter.Signatures[1].SignedAt = created.AddDate(0, 1, 2).Unix()
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err == nil {
+		t.Fatal("letter with modified counter-signature time should fail")
+	}
+	letter.Signatures[1].SignedAt = 0
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, testTrustStore)
+	if err == nil {
+		t.Fatal("letter with removed counter-signature time should fail")
+	}
+}
+
+func TestSignetCertification(t *testing.T) {
+	t.Parallel()
+
+	trustStore := NewMemTrustStore()
+	makeSignet := func(id string, trusted bool) *Signet {
+		t.Helper()
+
+		signet, err := GenerateSignet("Ed25519", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signet.ID = id
+		signet.Info = &SignetInfo{
+			Name:    id,
+			Created: time.Now(),
+		}
+		if trusted {
+			err = trustStore.StoreSignet(signet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rcpt, err := signet.AsRecipient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = trustStore.StoreSignet(rcpt)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return signet
+	}
+	certify := func(signet *Signet, certifierID string) {
+		t.Helper()
+
+		err := signet.Certify(&Envelope{
+			Version: 1,
+			SuiteID: SuiteSign,
+			Senders: []*Signet{{ID: certifierID}},
+		}, trustStore)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certifier1 := makeSignet("test-certifier-1", true)
+	certifier2 := makeSignet("test-certifier-2", true)
+	subject := makeSignet("test-certified-signet", false)
+
+	// not certified
+	err := subject.Verify(trustStore)
+	if !errors.Is(err, ErrSignetNotCertified) {
+		t.Fatalf("uncertified signet should fail with ErrSignetNotCertified, got: %v", err)
+	}
+
+	// certify
+	certify(subject, certifier1.ID)
+	certify(subject, certifier2.ID)
+	report, err := subject.VerifyDetailed(trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.VerifiedSigners()) != 2 {
+		t.Fatalf("expected two certifiers, got %v", report.VerifiedSigners())
+	}
+
+	// certification is kept for the recipient and survives serialization
+	rcpt, err := subject.AsRecipient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := rcpt.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcpt, err = SignetFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcpt.Verify(trustStore)
+	if err != nil {
+		t.Fatalf("certification of recipient should verify: %s", err)
+	}
+
+	// unknown certifiers
+	err = rcpt.Verify(NewMemTrustStore())
+	if err == nil {
+		t.Fatal("certification without known certifiers should fail")
+	}
+
+	// modified info
+	rcpt.Info = &SignetInfo{Name: "someone else"}
+	err = rcpt.Verify(trustStore)
+	if err == nil {
+		t.Fatal("certification of modified signet should fail")
+	}
+
+	// modified key
+	other := makeSignet(subject.ID, false)
+	other.Signature = subject.Signature
+	err = other.Verify(trustStore)
+	if err == nil {
+		t.Fatal("certification of different key should fail")
+	}
+
+	// ordinary signatures over the certified data
+	certifiedData, err := subject.compileCertificationData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Envelope{
+		Version: 1,
+		SuiteID: SuiteSign,
+		Senders: []*Signet{{ID: certifier1.ID}},
+	}
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter, err := s.Close(certifiedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter.Data = nil
+	forged, err := subject.AsRecipient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.Signature = letter
+	err = forged.Verify(trustStore)
+	if err == nil {
+		t.Fatal("ordinary signature should not be accepted as certification")
+	}
+}
+
+func TestSignetProtection(t *testing.T) {
+	t.Parallel()
+
+	trustStore := NewMemTrustStore()
+
+	// create key for protection
+	protectionKey := &Signet{
+		Version: 1,
+		ID:      "test-protection-key",
+		Scheme:  SignetSchemeKey,
+	}
+	var err error
+	protectionKey.Key, err = RandomBytes(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = trustStore.StoreSignet(protectionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create signet and recipient
+	signet, err := GenerateSignet("Ed25519", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet.ID = "test-protected-signet"
+	err = signet.StoreKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalKey := signet.Key
+	rcpt, err := signet.AsRecipient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = trustStore.StoreSignet(rcpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// protect
+	err = signet.Protect(&Envelope{
+		Version: 1,
+		SuiteID: SuiteKey,
+		Secrets: []*Signet{{ID: protectionKey.ID, Scheme: SignetSchemeKey}},
+	}, trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signet.Protection.Secrets) != 1 || len(signet.Protection.Secrets[0].Key) != 0 {
+		t.Fatal("protection should reference the secret without key material")
+	}
+
+	// serialize and check that key cannot be loaded
+	data, err := signet.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SignetFromBytes(data)
+	if !errors.Is(err, tools.ErrProtected) {
+		t.Fatalf("loading protected signet should fail with ErrProtected, got: %v", err)
+	}
+	err = trustStore.StoreSignet(signet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// use protected signet from trust store
+	e := &Envelope{
+		Version: 1,
+		SuiteID: SuiteSign,
+		Senders: []*Signet{{ID: signet.ID}},
+	}
+	s, err := e.Correspondence(trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = letter.Verify(getSuite(t, SuiteSign).Provides, trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signet.Protection == nil {
+		t.Fatal("signet in trust store should still be protected")
+	}
+
+	// unprotect
+	err = signet.Unprotect(trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signet.Protection != nil || string(signet.Key) != string(originalKey) {
+		t.Fatal("unprotected signet should have the original key")
+	}
+
+	// unprotect without protection key
+	err = signet.Protect(&Envelope{
+		Version: 1,
+		SuiteID: SuiteKey,
+		Secrets: []*Signet{{ID: protectionKey.ID, Scheme: SignetSchemeKey}},
+	}, trustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = signet.Unprotect(NewMemTrustStore())
+	if err == nil {
+		t.Fatal("unprotecting without the protection key should fail")
+	}
+}
@@ -0,0 +1,112 @@
+package jess
+
+import (
+	"crypto/ed25519"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHKeyImport(t *testing.T) {
+	t.Parallel()
+
+	pubKey, privKey, err := ed25519.GenerateKey(Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPubKey)))
+
+	// import public key, with and without comment or options
+	for _, text := range []string{
+		authorizedKey,
+		authorizedKey + " alice@example.com",
+		`no-pty,command="echo hi" ` + authorizedKey + " alice@example.com",
+	} {
+		if !IsSSHPublicKey(text) {
+			t.Errorf("%q should be detected as OpenSSH public key", text)
+		}
+		signet, err := SignetFromSSHPublicKey(text)
+		if err != nil {
+			t.Fatalf("failed to import %q: %s", text, err)
+		}
+		if !signet.Public || signet.Scheme != "Ed25519" {
+			t.Fatalf("unexpected signet: public=%v scheme=%s", signet.Public, signet.Scheme)
+		}
+		exported, err := signet.SSHPublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exported != authorizedKey {
+			t.Fatalf("exported key %q does not match %q", exported, authorizedKey)
+		}
+		if strings.HasSuffix(text, "alice@example.com") &&
+			(signet.Info == nil || signet.Info.Name != "alice@example.com") {
+			t.Fatal("comment should be used as name")
+		}
+	}
+
+	// import private key
+	block, err := ssh.MarshalPrivateKey(privKey, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet, err := SignetFromSSHPrivateKey(pem.EncodeToMemory(block))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signet.Public {
+		t.Fatal("signet should not be public")
+	}
+	exported, err := signet.SSHPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != authorizedKey {
+		t.Fatalf("exported key %q does not match %q", exported, authorizedKey)
+	}
+
+	// the imported signet must be usable with the signing tool
+	loaded, err := SignetFromBase58(mustToBase58(t, signet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = loaded.LoadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privKey.Equal(loaded.PrivateKey()) {
+		t.Fatal("private key does not match after serialization")
+	}
+
+	// encrypted private keys are not supported
+	block, err = ssh.MarshalPrivateKeyWithPassphrase(privKey, "", []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SignetFromSSHPrivateKey(pem.EncodeToMemory(block))
+	if err == nil {
+		t.Fatal("encrypted private key should not be accepted")
+	}
+
+	// other key types are not supported
+	_, err = SignetFromSSHPublicKey("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQDM")
+	if err == nil {
+		t.Fatal("RSA key should not be accepted")
+	}
+}
+
+func mustToBase58(t *testing.T, signet *Signet) string {
+	t.Helper()
+
+	data, err := signet.ToBase58()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
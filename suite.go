@@ -14,4 +14,9 @@ type Suite struct {
 	Provides      *Requirements
 	SecurityLevel int
 	Status        uint8
+
+	// Padding is the padding policy of the suite, see PaddingNone and others.
+	Padding string
+	// Compression is the compression algorithm of the suite, see CompressionNone and others.
+	Compression string
 }
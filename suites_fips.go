@@ -0,0 +1,20 @@
+package jess
+
+var (
+	// SuiteSignFIPSV1 is a cipher suite for signing (no encryption) with only FIPS approved algorithms.
+	SuiteSignFIPSV1 = registerSuite(&Suite{
+		ID:            "sign_fips_v1",
+		Tools:         []string{"ECDSA-P256(SHA2-256)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignFileFIPSV1 is a cipher suite for signing files (no encryption) with only FIPS approved algorithms.
+	SuiteSignFileFIPSV1 = registerSuite(&Suite{
+		ID:            "signfile_fips_v1",
+		Tools:         []string{"ECDSA-P256(SHA2-256)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+)
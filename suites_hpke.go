@@ -0,0 +1,22 @@
+package jess
+
+var (
+	// SuiteRcptOnlyHPKEV1 is a cipher suite for encrypting for someone, but without verifying the sender/source.
+	// The data is encrypted for a single recipient with HPKE in the base mode (RFC 9180), see HPKESeal.
+	SuiteRcptOnlyHPKEV1 = registerSuite(&Suite{
+		ID:            "rcpt_hpke_v1",
+		Tools:         []string{"HPKE-X25519"},
+		Provides:      NewRequirements().Remove(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteAuthHPKEV1 is a cipher suite for encrypting for someone, verifying the sender without signatures.
+	// The data is encrypted for a single recipient with HPKE in the auth mode (RFC 9180), which requires exactly one sender, see HPKESeal.
+	SuiteAuthHPKEV1 = registerSuite(&Suite{
+		ID:            "auth_hpke_v1",
+		Tools:         []string{"HPKE-AUTH-X25519"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+)
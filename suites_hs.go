@@ -0,0 +1,44 @@
+package jess
+
+var (
+	// SuiteRcptOnlyHSV1 is a high-security cipher suite for encrypting for someone, but without verifying the sender/source.
+	SuiteRcptOnlyHSV1 = registerSuite(&Suite{
+		ID:            "rcpt_hs_v1",
+		Tools:         []string{"ECDH-X448", "HKDF(SHA2-512)", "AES256-GCM"},
+		Provides:      NewRequirements().Remove(SenderAuthentication),
+		SecurityLevel: 224,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteCompleteHSV1 is a high-security cipher suite for both encrypting for someone and signing.
+	SuiteCompleteHSV1 = registerSuite(&Suite{
+		ID:            "hs_v1",
+		Tools:         []string{"ECDH-X448", "Ed448(SHA2-512)", "HKDF(SHA2-512)", "AES256-GCM"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 224,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignHSV1 is a high-security cipher suite for signing (no encryption).
+	SuiteSignHSV1 = registerSuite(&Suite{
+		ID:            "sign_hs_v1",
+		Tools:         []string{"Ed448(SHA2-512)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 224,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignFileHSV1 is a high-security cipher suite for signing files (no encryption).
+	SuiteSignFileHSV1 = registerSuite(&Suite{
+		ID:            "signfile_hs_v1",
+		Tools:         []string{"Ed448(SHA2-512)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 224,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteWireHSV1 is a high-security cipher suite for network communication, including authentication of the server, but not the client.
+	SuiteWireHSV1 = registerSuite(&Suite{
+		ID:            "w_hs1",
+		Tools:         []string{"ECDH-X448", "HKDF(SHA2-512)", "AES256-GCM"},
+		Provides:      NewRequirements().Remove(SenderAuthentication),
+		SecurityLevel: 224,
+		Status:        SuiteStatusPermitted,
+	})
+)
@@ -0,0 +1,55 @@
+package jess
+
+// Hybrid post-quantum suites combine classic and post-quantum algorithms.
+// The key material of both is mixed by the KDF, so a letter stays confidential as long as one of them is unbroken.
+// Signatures of both are required, so a letter stays authentic as long as one of them is unbroken.
+var (
+	// SuiteRcptOnlyPQV1 is a cipher suite for encrypting for someone with hybrid X25519 and ML-KEM-768 key establishment, but without verifying the sender/source.
+	SuiteRcptOnlyPQV1 = registerSuite(&Suite{
+		ID:            "rcpt_pq_v1",
+		Tools:         []string{"ECDH-X25519", "ML-KEM-768", "BLAKE3-KDF", "CHACHA20-POLY1305"},
+		Provides:      NewRequirements().Remove(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteCompletePQV1 is a cipher suite for both encrypting for someone with hybrid X25519 and ML-KEM-768 key establishment and signing.
+	SuiteCompletePQV1 = registerSuite(&Suite{
+		ID:            "pq_v1",
+		Tools:         []string{"ECDH-X25519", "ML-KEM-768", "Ed25519(BLAKE3)", "BLAKE3-KDF", "CHACHA20-POLY1305"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteWirePQV1 is a cipher suite for network communication with hybrid X25519 and ML-KEM-768 key establishment, including authentication of the server, but not the client.
+	SuiteWirePQV1 = registerSuite(&Suite{
+		ID:            "w_pq1",
+		Tools:         []string{"ECDH-X25519", "ML-KEM-768", "BLAKE3-KDF", "CHACHA20-POLY1305"},
+		Provides:      NewRequirements().Remove(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignPQV1 is a cipher suite for signing (no encryption) with both Ed25519 and ML-DSA-65.
+	SuiteSignPQV1 = registerSuite(&Suite{
+		ID:            "sign_pq_v1",
+		Tools:         []string{"Ed25519(BLAKE3)", "ML-DSA-65(BLAKE3)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignFilePQV1 is a cipher suite for signing files (no encryption) with both Ed25519 and ML-DSA-65.
+	SuiteSignFilePQV1 = registerSuite(&Suite{
+		ID:            "signfile_pq_v1",
+		Tools:         []string{"Ed25519(BLAKE3)", "ML-DSA-65(BLAKE3)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteSignFileSLHV1 is a cipher suite for signing files (no encryption) with both Ed25519 and the conservative, hash-based SLH-DSA-SHA2-128s.
+	SuiteSignFileSLHV1 = registerSuite(&Suite{
+		ID:            "signfile_slh_v1",
+		Tools:         []string{"Ed25519(BLAKE3)", "SLH-DSA-SHA2-128s(BLAKE3)"},
+		Provides:      newEmptyRequirements().Add(Integrity).Add(SenderAuthentication),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+)
@@ -1,6 +1,7 @@
 package jess
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strings"
@@ -190,6 +191,14 @@ func suiteBullshitCheck(suite *Suite) error { //nolint:maintidx
 		case tools.PurposeKeyEncapsulation:
 			s.keyEncapsulators = append(s.keyEncapsulators, logic)
 			s.toolRequirements.Add(RecipientAuthentication)
+			if tool.Info.HasOption(tools.OptionAuthenticatesSender) {
+				s.toolRequirements.Add(SenderAuthentication)
+			}
+			if tool.Info.HasOption(tools.OptionSealsPayload) {
+				s.payloadSealer = logic
+				s.toolRequirements.Add(Confidentiality)
+				s.toolRequirements.Add(Integrity)
+			}
 
 		case tools.PurposeSigning:
 			s.signers = append(s.signers, logic)
@@ -312,7 +321,7 @@ func suiteBullshitCheck(suite *Suite) error { //nolint:maintidx
 	}
 
 	// check if we are missing a kdf, but need one
-	if s.kdf == nil && len(s.signers) != len(s.envelope.suite.Tools) {
+	if s.kdf == nil && s.payloadSealer == nil && len(s.signers) != len(s.envelope.suite.Tools) {
 		return errors.New("missing a key derivation tool")
 	}
 
@@ -416,6 +425,13 @@ func computeSuiteAttributes(toolIDs []string, assumeKey bool) *Suite {
 
 		case tools.PurposeKeyEncapsulation:
 			newSuite.Provides.Add(RecipientAuthentication)
+			if tool.Info.HasOption(tools.OptionAuthenticatesSender) {
+				newSuite.Provides.Add(SenderAuthentication)
+			}
+			if tool.Info.HasOption(tools.OptionSealsPayload) {
+				newSuite.Provides.Add(Confidentiality)
+				newSuite.Provides.Add(Integrity)
+			}
 
 		case tools.PurposeSigning:
 			newSuite.Provides.Add(Integrity)
@@ -477,3 +493,48 @@ func computeSuiteAttributes(toolIDs []string, assumeKey bool) *Suite {
 
 	return newSuite
 }
+
+func TestSuiteKeySIV(t *testing.T) {
+	t.Parallel()
+
+	e, err := setupEnvelopeAndTrustStore(t, getSuite(t, SuiteKeySIV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := e.Correspondence(testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every encryption prepends a new random nonce to the ciphertext.
+	letter1, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	letter2, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonceSize := 12
+	if len(letter1.Data) <= nonceSize || len(letter2.Data) <= nonceSize {
+		t.Fatal("ciphertext is too short to hold a nonce")
+	}
+	if bytes.Equal(letter1.Data[:nonceSize], letter2.Data[:nonceSize]) {
+		t.Fatal("closing the same data twice should use different nonces")
+	}
+
+	opened, err := letter1.Open(e.suite.Provides, testTrustStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != testData1 {
+		t.Fatal("opened data does not match")
+	}
+
+	// tampered ciphertext is rejected
+	letter2.Data[len(letter2.Data)-1] ^= 0x01
+	_, err = letter2.Open(e.suite.Provides, testTrustStore)
+	if err == nil {
+		t.Fatal("tampered ciphertext should be rejected")
+	}
+}
@@ -0,0 +1,30 @@
+package jess
+
+var (
+	// SuiteKeyV3 is a cipher suite for encryption with a key.
+	// XChaCha20-Poly1305 is used, as its extended nonce is safe for encrypting a very large number of letters with the same key.
+	SuiteKeyV3 = registerSuite(&Suite{
+		ID:            "key_v3",
+		Tools:         []string{"BLAKE3-KDF", "XCHACHA20-POLY1305"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuiteKeySIV is a cipher suite for encryption with a key.
+	// AES-GCM-SIV is used, as it does not break down if a nonce is ever reused.
+	SuiteKeySIV = registerSuite(&Suite{
+		ID:            "key_siv_v1",
+		Tools:         []string{"BLAKE3-KDF", "AES256-GCM-SIV"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+	// SuitePasswordV3 is a cipher suite for encryption with a password, using Argon2id.
+	SuitePasswordV3 = registerSuite(&Suite{
+		ID:            "pw_v3",
+		Tools:         []string{"ARGON2ID(m=65536,t=3,p=4)", "BLAKE3-KDF", "CHACHA20-POLY1305"},
+		Provides:      NewRequirements(),
+		SecurityLevel: 128,
+		Status:        SuiteStatusPermitted,
+	})
+)
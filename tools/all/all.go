@@ -4,6 +4,9 @@ package all
 import (
 	// Import all tool subpackages.
 	_ "github.com/safing/jess/tools/blake3"
+	_ "github.com/safing/jess/tools/circl"
 	_ "github.com/safing/jess/tools/ecdh"
 	_ "github.com/safing/jess/tools/gostdlib"
+	_ "github.com/safing/jess/tools/hpke"
+	_ "github.com/safing/jess/tools/tink"
 )
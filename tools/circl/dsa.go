@@ -0,0 +1,206 @@
+// Package circl provides post-quantum and Curve448 tools based on the Cloudflare Interoperable Reusable Cryptographic Library (CIRCL).
+package circl
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+	"github.com/cloudflare/circl/sign/slhdsa"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+var pqSigningInfo = &tools.ToolInfo{
+	Purpose: tools.PurposeSigning,
+	Options: []uint8{tools.OptionNeedsManagedHasher},
+}
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: pqSigningInfo.With(&tools.ToolInfo{
+			Name:          "ML-DSA-65",
+			SecurityLevel: 192,
+			Comment:       "post-quantum signatures, NIST FIPS 204",
+			Author:        "Ducas et al., 2018",
+		}),
+		Factory: func() tools.ToolLogic { return &PQSigner{scheme: mldsa65.Scheme(), storeSeed: true} },
+	})
+	tools.Register(&tools.Tool{
+		Info: pqSigningInfo.With(&tools.ToolInfo{
+			Name:          "SLH-DSA-SHA2-128s",
+			SecurityLevel: 128,
+			Comment:       "post-quantum hash-based signatures, NIST FIPS 205",
+			Author:        "Bernstein et al., 2015",
+		}),
+		Factory: func() tools.ToolLogic { return &PQSigner{scheme: slhdsa.SHA2_128s.Scheme()} },
+	})
+}
+
+// PQSigner implements the cryptographic interface for the post-quantum signature schemes of CIRCL.
+type PQSigner struct {
+	tools.ToolLogicBase
+	scheme sign.Scheme
+	// storeSeed defines whether private keys are stored as their seed, instead of the full key.
+	storeSeed bool
+}
+
+// Sign implements the ToolLogic interface.
+// ML-DSA signatures are hedged with randomness from crypto/rand, as CIRCL does not accept another random source for them.
+// Other schemes are randomized with the helper's random source, if they support it.
+func (dsa *PQSigner) Sign(data, associatedData []byte, signet tools.SignetInt) ([]byte, error) {
+	privKey, ok := signet.PrivateKey().(sign.PrivateKey)
+	if !ok || privKey.Scheme().Name() != dsa.scheme.Name() {
+		return nil, tools.ErrInvalidKey
+	}
+
+	hashsum, err := dsa.ManagedHashSum()
+	if err != nil {
+		return nil, err
+	}
+
+	// The crypto.Signer interface of ML-DSA is deterministic, so sign hedged directly.
+	// This ignores the helper's random source.
+	if mldsaKey, ok := privKey.(*mldsa65.PrivateKey); ok {
+		signature := make([]byte, mldsa65.SignatureSize)
+		err = mldsa65.SignTo(mldsaKey, hashsum, nil, true, signature)
+		if err != nil {
+			return nil, err
+		}
+		return signature, nil
+	}
+
+	return privKey.Sign(dsa.Helper().Random(), hashsum, crypto.Hash(0))
+}
+
+// Verify implements the ToolLogic interface.
+func (dsa *PQSigner) Verify(data, associatedData, signature []byte, signet tools.SignetInt) error {
+	pubKey, ok := signet.PublicKey().(sign.PublicKey)
+	if !ok || pubKey.Scheme().Name() != dsa.scheme.Name() {
+		return tools.ErrInvalidKey
+	}
+
+	hashsum, err := dsa.ManagedHashSum()
+	if err != nil {
+		return err
+	}
+
+	if !dsa.scheme.Verify(pubKey, hashsum, signature, nil) {
+		return errors.New("signature invalid")
+	}
+	return nil
+}
+
+// LoadKey implements the ToolLogic interface.
+func (dsa *PQSigner) LoadKey(signet tools.SignetInt) error {
+	var pubKey sign.PublicKey
+	var privKey sign.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load keys
+	data := c.CompileData()
+	switch {
+	case public:
+		pubKey, err = dsa.scheme.UnmarshalBinaryPublicKey(data)
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+	case dsa.storeSeed:
+		if len(data) != dsa.scheme.SeedSize() {
+			return tools.ErrInvalidKey
+		}
+		pubKey, privKey = dsa.scheme.DeriveKey(data)
+	default:
+		privKey, err = dsa.scheme.UnmarshalBinaryPrivateKey(data)
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+		var ok bool
+		pubKey, ok = privKey.Public().(sign.PublicKey)
+		if !ok {
+			return tools.ErrInvalidKey
+		}
+	}
+
+	if privKey == nil {
+		signet.SetLoadedKeys(pubKey, nil)
+	} else {
+		signet.SetLoadedKeys(pubKey, privKey)
+	}
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (dsa *PQSigner) StoreKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store keys
+	var data []byte
+	var err error
+	if public {
+		signPubKey, ok := pubKey.(sign.PublicKey)
+		if !ok || signPubKey.Scheme().Name() != dsa.scheme.Name() {
+			return tools.ErrInvalidKey
+		}
+		data, err = signPubKey.MarshalBinary()
+	} else {
+		signPrivKey, ok := privKey.(sign.PrivateKey)
+		if !ok || signPrivKey.Scheme().Name() != dsa.scheme.Name() {
+			return tools.ErrInvalidKey
+		}
+		if dsa.storeSeed {
+			seeded, ok := privKey.(sign.Seeded)
+			if !ok || len(seeded.Seed()) != dsa.scheme.SeedSize() {
+				return tools.ErrInvalidKey
+			}
+			data = seeded.Seed()
+		} else {
+			data, err = signPrivKey.MarshalBinary()
+		}
+	}
+	if err != nil {
+		return tools.ErrInvalidKey
+	}
+	c.Append(data)
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (dsa *PQSigner) GenerateKey(signet tools.SignetInt) error {
+	// generate from seed, so that the helper's random source is used
+	seed := make([]byte, dsa.scheme.SeedSize())
+	_, err := io.ReadFull(dsa.Helper().Random(), seed)
+	if err != nil {
+		return err
+	}
+	defer dsa.Helper().Burn(seed)
+
+	pubKey, privKey := dsa.scheme.DeriveKey(seed)
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface.
+// The keys of CIRCL are opaque and cannot be burned, see known issues in the project's README.
+func (dsa *PQSigner) BurnKey(signet tools.SignetInt) error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package circl
+
+import (
+	"crypto"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/sign/ed448"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: &tools.ToolInfo{
+			Name:          "Ed448",
+			Purpose:       tools.PurposeSigning,
+			Options:       []uint8{tools.OptionNeedsManagedHasher},
+			SecurityLevel: 224,
+			Comment:       "RFC 8032",
+			Author:        "Mike Hamburg, 2015",
+		},
+		Factory: func() tools.ToolLogic { return &Ed448{} },
+	})
+}
+
+// Ed448 implements the cryptographic interface for Ed448 signatures.
+type Ed448 struct {
+	tools.ToolLogicBase
+}
+
+// Sign implements the ToolLogic interface.
+func (ed *Ed448) Sign(data, associatedData []byte, signet tools.SignetInt) ([]byte, error) {
+	privKey, ok := signet.PrivateKey().(ed448.PrivateKey)
+	if !ok || len(privKey) != ed448.PrivateKeySize {
+		return nil, tools.ErrInvalidKey
+	}
+
+	hashsum, err := ed.ManagedHashSum()
+	if err != nil {
+		return nil, err
+	}
+
+	return ed448.Sign(privKey, hashsum, ""), nil
+}
+
+// Verify implements the ToolLogic interface.
+func (ed *Ed448) Verify(data, associatedData, signature []byte, signet tools.SignetInt) error {
+	pubKey, ok := signet.PublicKey().(ed448.PublicKey)
+	if !ok || len(pubKey) != ed448.PublicKeySize {
+		return tools.ErrInvalidKey
+	}
+
+	hashsum, err := ed.ManagedHashSum()
+	if err != nil {
+		return err
+	}
+
+	if !ed448.Verify(pubKey, hashsum, signature, "") {
+		return errors.New("signature invalid")
+	}
+	return nil
+}
+
+// LoadKey implements the ToolLogic interface.
+func (ed *Ed448) LoadKey(signet tools.SignetInt) error {
+	var pubKey crypto.PublicKey
+	var privKey crypto.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load keys
+	data := c.CompileData()
+	if public {
+		if len(data) != ed448.PublicKeySize {
+			return tools.ErrInvalidKey
+		}
+		pubKey = ed448.PublicKey(data)
+	} else {
+		// private keys are stored as their seed
+		if len(data) != ed448.SeedSize {
+			return tools.ErrInvalidKey
+		}
+		edPrivKey := ed448.NewKeyFromSeed(data)
+		pubKey = edPrivKey.Public()
+		privKey = edPrivKey
+	}
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (ed *Ed448) StoreKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store keys
+	if public {
+		edPubKey, ok := pubKey.(ed448.PublicKey)
+		if !ok {
+			return tools.ErrInvalidKey
+		}
+		c.Append(edPubKey)
+	} else {
+		edPrivKey, ok := privKey.(ed448.PrivateKey)
+		if !ok {
+			return tools.ErrInvalidKey
+		}
+		c.Append(edPrivKey.Seed())
+	}
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (ed *Ed448) GenerateKey(signet tools.SignetInt) error {
+	// generate from seed, so that the private key can be stored as its seed
+	seed := make([]byte, ed448.SeedSize)
+	_, err := io.ReadFull(ed.Helper().Random(), seed)
+	if err != nil {
+		return err
+	}
+	defer ed.Helper().Burn(seed)
+
+	privKey := ed448.NewKeyFromSeed(seed)
+	signet.SetLoadedKeys(privKey.Public(), privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface. This is currently ineffective, see known issues in the project's README.
+func (ed *Ed448) BurnKey(signet tools.SignetInt) error {
+	// burn private key
+	if privKey, ok := signet.PrivateKey().(ed448.PrivateKey); ok {
+		ed.Helper().Burn(privKey)
+	}
+
+	return nil
+}
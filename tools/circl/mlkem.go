@@ -0,0 +1,186 @@
+package circl
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: &tools.ToolInfo{
+			Name:          "ML-KEM-768",
+			Purpose:       tools.PurposeKeyEncapsulation,
+			SecurityLevel: 192,
+			Comment:       "post-quantum key encapsulation, NIST FIPS 203",
+			Author:        "Bos et al., 2018",
+		},
+		Factory: func() tools.ToolLogic { return &MLKEM768{} },
+	})
+}
+
+// mlkemKeyWrapInfo is the HKDF info used to derive the key wrapping stream from the ML-KEM shared key.
+const mlkemKeyWrapInfo = "jess ML-KEM-768 key wrap"
+
+// MLKEM768 implements the cryptographic interface for ML-KEM-768 key encapsulation.
+// As ML-KEM generates its own shared key, the given key is wrapped with a key stream derived from the shared key.
+type MLKEM768 struct {
+	tools.ToolLogicBase
+}
+
+// EncapsulateKey implements the ToolLogic interface.
+func (kem *MLKEM768) EncapsulateKey(key []byte, signet tools.SignetInt) ([]byte, error) {
+	pubKey, ok := signet.PublicKey().(*mlkem768.PublicKey)
+	if !ok || pubKey == nil {
+		return nil, tools.ErrInvalidKey
+	}
+
+	// encapsulate shared key
+	seed := make([]byte, mlkem768.EncapsulationSeedSize)
+	_, err := io.ReadFull(kem.Helper().Random(), seed)
+	if err != nil {
+		return nil, err
+	}
+	defer kem.Helper().Burn(seed)
+	ciphertext := make([]byte, mlkem768.CiphertextSize)
+	sharedKey := make([]byte, mlkem768.SharedKeySize)
+	pubKey.EncapsulateTo(ciphertext, sharedKey, seed)
+	defer kem.Helper().Burn(sharedKey)
+
+	// wrap key
+	wrappedKey, err := mlkemWrapKey(sharedKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ciphertext, wrappedKey...), nil
+}
+
+// UnwrapKey implements the ToolLogic interface.
+func (kem *MLKEM768) UnwrapKey(wrappedKey []byte, signet tools.SignetInt) ([]byte, error) {
+	privKey, ok := signet.PrivateKey().(*mlkem768.PrivateKey)
+	if !ok || privKey == nil {
+		return nil, tools.ErrInvalidKey
+	}
+	if len(wrappedKey) <= mlkem768.CiphertextSize {
+		return nil, errors.New("wrapped key too short")
+	}
+
+	// decapsulate shared key
+	sharedKey := make([]byte, mlkem768.SharedKeySize)
+	privKey.DecapsulateTo(sharedKey, wrappedKey[:mlkem768.CiphertextSize])
+	defer kem.Helper().Burn(sharedKey)
+
+	// unwrap key
+	return mlkemWrapKey(sharedKey, wrappedKey[mlkem768.CiphertextSize:])
+}
+
+// mlkemWrapKey xors the key with a key stream derived from the shared key.
+// This is secure, as every shared key is only ever used once.
+func mlkemWrapKey(sharedKey, key []byte) ([]byte, error) {
+	stream := make([]byte, len(key))
+	_, err := io.ReadFull(hkdf.New(sha256.New, sharedKey, nil, []byte(mlkemKeyWrapInfo)), stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key wrapping stream: %w", err)
+	}
+
+	for i := range stream {
+		stream[i] ^= key[i]
+	}
+	return stream, nil
+}
+
+// LoadKey implements the ToolLogic interface.
+func (kem *MLKEM768) LoadKey(signet tools.SignetInt) error {
+	var pubKey crypto.PublicKey
+	var privKey crypto.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load keys
+	data := c.CompileData()
+	if public {
+		mlkemPubKey := &mlkem768.PublicKey{}
+		if len(data) != mlkem768.PublicKeySize || mlkemPubKey.Unpack(data) != nil {
+			return tools.ErrInvalidKey
+		}
+		pubKey = mlkemPubKey
+	} else {
+		mlkemPrivKey := &mlkem768.PrivateKey{}
+		if len(data) != mlkem768.PrivateKeySize || mlkemPrivKey.Unpack(data) != nil {
+			return tools.ErrInvalidKey
+		}
+		privKey = mlkemPrivKey
+		pubKey = mlkemPrivKey.Public()
+	}
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (kem *MLKEM768) StoreKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store keys
+	var data []byte
+	var err error
+	if public {
+		mlkemPubKey, ok := pubKey.(*mlkem768.PublicKey)
+		if !ok {
+			return tools.ErrInvalidKey
+		}
+		data, err = mlkemPubKey.MarshalBinary()
+	} else {
+		mlkemPrivKey, ok := privKey.(*mlkem768.PrivateKey)
+		if !ok {
+			return tools.ErrInvalidKey
+		}
+		data, err = mlkemPrivKey.MarshalBinary()
+	}
+	if err != nil {
+		return tools.ErrInvalidKey
+	}
+	c.Append(data)
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (kem *MLKEM768) GenerateKey(signet tools.SignetInt) error {
+	pubKey, privKey, err := mlkem768.GenerateKeyPair(kem.Helper().Random())
+	if err != nil {
+		return err
+	}
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface.
+// The keys of CIRCL are opaque and cannot be burned, see known issues in the project's README.
+func (kem *MLKEM768) BurnKey(signet tools.SignetInt) error {
+	return nil
+}
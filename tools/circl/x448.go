@@ -0,0 +1,154 @@
+package circl
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/dh/x448"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: &tools.ToolInfo{
+			Name:          "ECDH-X448",
+			Purpose:       tools.PurposeKeyExchange,
+			SecurityLevel: 224,
+			Comment:       "RFC 7748",
+			Author:        "Mike Hamburg, 2015",
+		},
+		Factory: func() tools.ToolLogic { return &X448Curve{} },
+	})
+}
+
+// X448Curve implements the cryptographic interface for the ECDH X448 key exchange.
+type X448Curve struct {
+	tools.ToolLogicBase
+}
+
+// MakeSharedKey implements the ToolLogic interface.
+func (ec *X448Curve) MakeSharedKey(local tools.SignetInt, remote tools.SignetInt) ([]byte, error) {
+	privKey, ok := local.PrivateKey().(x448.Key)
+	if !ok {
+		return nil, tools.ErrInvalidKey
+	}
+	pubKey, ok := remote.PublicKey().(x448.Key)
+	if !ok {
+		return nil, tools.ErrInvalidKey
+	}
+
+	var shared x448.Key
+	if !x448.Shared(&shared, &privKey, &pubKey) {
+		return nil, errors.New("public key is a low order point")
+	}
+	return shared[:], nil
+}
+
+// LoadKey implements the ToolLogic interface.
+func (ec *X448Curve) LoadKey(signet tools.SignetInt) error {
+	var pubKey crypto.PublicKey
+	var privKey crypto.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load public key
+	data, err := c.Get(x448.Size)
+	if err != nil {
+		return tools.ErrInvalidKey
+	}
+	var pubKeyData x448.Key
+	copy(pubKeyData[:], data)
+	pubKey = pubKeyData
+
+	// load private key
+	if !public {
+		data, err = c.Get(x448.Size)
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+		var privKeyData x448.Key
+		copy(privKeyData[:], data)
+		privKey = privKeyData
+	}
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (ec *X448Curve) StoreKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store keys
+	pubKeyData, ok := pubKey.(x448.Key)
+	if !ok {
+		return fmt.Errorf("public key of invalid type %T", pubKey)
+	}
+	c.Append(pubKeyData[:])
+	if !public {
+		privKeyData, ok := privKey.(x448.Key)
+		if !ok {
+			return fmt.Errorf("private key of invalid type %T", privKey)
+		}
+		c.Append(privKeyData[:])
+	}
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (ec *X448Curve) GenerateKey(signet tools.SignetInt) error {
+	var pubKey, privKey x448.Key
+
+	// generate keys
+	_, err := io.ReadFull(ec.Helper().Random(), privKey[:])
+	if err != nil {
+		return err
+	}
+	x448.KeyGen(&pubKey, &privKey)
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface. This is currently ineffective, see known issues in the project's README.
+func (ec *X448Curve) BurnKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+
+	// burn public key
+	if pubKey != nil {
+		data, ok := pubKey.(x448.Key)
+		if ok {
+			ec.Helper().Burn(data[:])
+		}
+	}
+
+	// burn private key
+	if privKey != nil {
+		data, ok := privKey.(x448.Key)
+		if ok {
+			ec.Helper().Burn(data[:])
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package ecdh
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/elliptic"
 	"fmt"
 	"math/big"
@@ -24,35 +25,43 @@ func init() {
 			Name:          "ECDH-P224",
 			SecurityLevel: 112,
 		}),
-		Factory: func() tools.ToolLogic { return &NistCurve{curve: ecdh.Generic(elliptic.P224())} },
+		Factory: func() tools.ToolLogic { return newNistCurve(elliptic.P224()) },
 	})
 	tools.Register(&tools.Tool{
 		Info: nistCurveInfo.With(&tools.ToolInfo{
 			Name:          "ECDH-P256",
 			SecurityLevel: 128,
 		}),
-		Factory: func() tools.ToolLogic { return &NistCurve{curve: ecdh.Generic(elliptic.P256())} },
+		Factory: func() tools.ToolLogic { return newNistCurve(elliptic.P256()) },
 	})
 	tools.Register(&tools.Tool{
 		Info: nistCurveInfo.With(&tools.ToolInfo{
 			Name:          "ECDH-P384",
 			SecurityLevel: 192,
 		}),
-		Factory: func() tools.ToolLogic { return &NistCurve{curve: ecdh.Generic(elliptic.P384())} },
+		Factory: func() tools.ToolLogic { return newNistCurve(elliptic.P384()) },
 	})
 	tools.Register(&tools.Tool{
 		Info: nistCurveInfo.With(&tools.ToolInfo{
 			Name:          "ECDH-P521",
 			SecurityLevel: 256,
 		}),
-		Factory: func() tools.ToolLogic { return &NistCurve{curve: ecdh.Generic(elliptic.P521())} },
+		Factory: func() tools.ToolLogic { return newNistCurve(elliptic.P521()) },
 	})
 }
 
 // NistCurve implements the cryptographic interface for ECDH key exchange with NIST curves.
 type NistCurve struct {
 	tools.ToolLogicBase
-	curve ecdh.KeyExchange
+	curve    ecdh.KeyExchange
+	stdCurve elliptic.Curve
+}
+
+func newNistCurve(curve elliptic.Curve) *NistCurve {
+	return &NistCurve{
+		curve:    ecdh.Generic(curve),
+		stdCurve: curve,
+	}
 }
 
 // MakeSharedKey implements the ToolLogic interface.
@@ -177,3 +186,57 @@ func (ec *NistCurve) BurnKey(signet tools.SignetInt) error {
 
 	return nil
 }
+
+// ImportStdKeys implements the ToolLogic interface.
+func (ec *NistCurve) ImportStdKeys(signet tools.SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	// The standard library represents keys of NIST curves as ECDSA keys.
+	ecPubKey, ok := pubKey.(*ecdsa.PublicKey)
+	if !ok || ecPubKey == nil || ecPubKey.Curve != ec.stdCurve {
+		return tools.ErrInvalidKey
+	}
+	point := ecdh.Point{
+		X: new(big.Int).Set(ecPubKey.X),
+		Y: new(big.Int).Set(ecPubKey.Y),
+	}
+	if privKey == nil {
+		signet.SetLoadedKeys(point, nil)
+		return nil
+	}
+
+	ecPrivKey, ok := privKey.(*ecdsa.PrivateKey)
+	if !ok || ecPrivKey == nil || ecPrivKey.D == nil {
+		return tools.ErrInvalidKey
+	}
+	signet.SetLoadedKeys(point, ecPrivKey.D.FillBytes(make([]byte, ec.scalarSize())))
+	return nil
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (ec *NistCurve) ExportStdKeys(signet tools.SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	point, ok := signet.PublicKey().(ecdh.Point)
+	if !ok {
+		return nil, nil, fmt.Errorf("public key of invalid type %T", signet.PublicKey())
+	}
+	ecPubKey := &ecdsa.PublicKey{
+		Curve: ec.stdCurve,
+		X:     new(big.Int).Set(point.X),
+		Y:     new(big.Int).Set(point.Y),
+	}
+	if signet.PrivateKey() == nil {
+		return ecPubKey, nil, nil
+	}
+
+	privKeyData, ok := signet.PrivateKey().([]byte)
+	if !ok || len(privKeyData) > ec.scalarSize() {
+		return nil, nil, fmt.Errorf("private key of invalid type %T", signet.PrivateKey())
+	}
+	return ecPubKey, &ecdsa.PrivateKey{
+		PublicKey: *ecPubKey,
+		D:         new(big.Int).SetBytes(privKeyData),
+	}, nil
+}
+
+// scalarSize returns the size of private keys (scalars) of the curve in bytes.
+func (ec *NistCurve) scalarSize() int {
+	return (ec.stdCurve.Params().BitSize + 7) / 8
+}
@@ -2,6 +2,7 @@ package ecdh
 
 import (
 	"crypto"
+	stdecdh "crypto/ecdh"
 	"fmt"
 
 	"github.com/aead/ecdh"
@@ -145,3 +146,51 @@ func (ec *X25519Curve) BurnKey(signet tools.SignetInt) error {
 
 	return nil
 }
+
+// ImportStdKeys implements the ToolLogic interface.
+func (ec *X25519Curve) ImportStdKeys(signet tools.SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	stdPubKey, ok := pubKey.(*stdecdh.PublicKey)
+	if !ok || stdPubKey == nil || stdPubKey.Curve() != stdecdh.X25519() {
+		return tools.ErrInvalidKey
+	}
+	var pubKeyData [32]byte
+	copy(pubKeyData[:], stdPubKey.Bytes())
+	if privKey == nil {
+		signet.SetLoadedKeys(pubKeyData, nil)
+		return nil
+	}
+
+	stdPrivKey, ok := privKey.(*stdecdh.PrivateKey)
+	if !ok || stdPrivKey == nil || stdPrivKey.Curve() != stdecdh.X25519() {
+		return tools.ErrInvalidKey
+	}
+	var privKeyData [32]byte
+	copy(privKeyData[:], stdPrivKey.Bytes())
+	signet.SetLoadedKeys(pubKeyData, privKeyData)
+	return nil
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (ec *X25519Curve) ExportStdKeys(signet tools.SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	pubKeyData, ok := signet.PublicKey().([32]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("public key of invalid type %T", signet.PublicKey())
+	}
+	stdPubKey, err := stdecdh.X25519().NewPublicKey(pubKeyData[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	if signet.PrivateKey() == nil {
+		return stdPubKey, nil, nil
+	}
+
+	privKeyData, ok := signet.PrivateKey().([32]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("private key of invalid type %T", signet.PrivateKey())
+	}
+	stdPrivKey, err := stdecdh.X25519().NewPrivateKey(privKeyData[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return stdPubKey, stdPrivKey, nil
+}
@@ -10,7 +10,7 @@ import (
 func init() {
 	aesCtrInfo := &tools.ToolInfo{
 		Purpose:   tools.PurposeCipher,
-		Options:   []uint8{tools.OptionHasState},
+		Options:   []uint8{tools.OptionStreaming, tools.OptionHasState},
 		NonceSize: aes.BlockSize,
 		Comment:   "aka Rijndael, FIPS 197",
 		Author:    "Vincent Rijmen and Joan Daemen, 1998",
@@ -10,7 +10,7 @@ import (
 func init() {
 	aesGcmInfo := &tools.ToolInfo{
 		Purpose:   tools.PurposeIntegratedCipher,
-		Options:   []uint8{tools.OptionHasState},
+		Options:   []uint8{tools.OptionStreaming, tools.OptionHasState},
 		NonceSize: 12, // standard nonce size for GCM in Golang stdlib
 		Comment:   "aka Rijndael, FIPS 197",
 		Author:    "Vincent Rijmen and Joan Daemen, 1998",
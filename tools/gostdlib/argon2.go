@@ -0,0 +1,112 @@
+package gostdlib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/safing/jess/tools"
+)
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: &tools.ToolInfo{
+			Name:          "ARGON2ID",
+			Purpose:       tools.PurposePassDerivation,
+			Options:       []uint8{tools.OptionNeedsDefaultKeySize, tools.OptionHasParameters},
+			SecurityLevel: 0, // security of default key size
+			Comment:       "RFC 9106, parameters: m=<memory in KiB>,t=<passes>,p=<parallelism>",
+			Author:        "Alex Biryukov, Daniel Dinu, Dmitry Khovratovich, 2015",
+		},
+		Factory: func() tools.ToolLogic {
+			// Defaults are the second recommended option of RFC 9106.
+			return &ARGON2ID{
+				memory:      64 * 1024, // 64 MiB
+				time:        3,
+				parallelism: 4,
+			}
+		},
+	})
+}
+
+// Upper bounds of the Argon2id parameters.
+// They protect against letters that specify parameters which would exhaust the resources of the opener.
+const (
+	argon2MaxMemory      = 2 * 1024 * 1024 // 2 GiB, as in the first recommended option of RFC 9106
+	argon2MaxTime        = 16
+	argon2MaxParallelism = 16
+)
+
+// ARGON2ID implements the cryptographic interface for Argon2id password derivation.
+type ARGON2ID struct {
+	tools.ToolLogicBase
+	memory      uint32 // Memory cost in KiB
+	time        uint32 // Number of passes over the memory
+	parallelism uint8  // Number of lanes
+}
+
+// SetParameters implements the ToolLogic interface.
+// Parameters are given as comma separated key-value pairs, eg. "m=65536,t=3,p=4". Missing parameters keep their default.
+// Parameters are limited to at most 2 GiB of memory, 16 passes and 16 lanes.
+func (a2 *ARGON2ID) SetParameters(params string) error {
+	if params == "" {
+		return nil
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			return fmt.Errorf("invalid parameter %q", param)
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid value for parameter %q: %w", key, err)
+		}
+
+		switch key {
+		case "m":
+			a2.memory = uint32(n)
+		case "t":
+			a2.time = uint32(n)
+		case "p":
+			if n > math.MaxUint8 {
+				return fmt.Errorf("parallelism must not exceed %d", math.MaxUint8)
+			}
+			a2.parallelism = uint8(n)
+		default:
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+
+	// check parameters
+	switch {
+	case a2.time < 1:
+		return errors.New("at least one pass is required")
+	case a2.time > argon2MaxTime:
+		return fmt.Errorf("passes must not exceed %d", argon2MaxTime)
+	case a2.parallelism < 1:
+		return errors.New("parallelism must be at least 1")
+	case a2.parallelism > argon2MaxParallelism:
+		return fmt.Errorf("parallelism must not exceed %d", argon2MaxParallelism)
+	case a2.memory < 8*uint32(a2.parallelism):
+		return errors.New("memory must be at least 8 KiB per lane")
+	case a2.memory > argon2MaxMemory:
+		return fmt.Errorf("memory must not exceed %d KiB", argon2MaxMemory)
+	}
+	return nil
+}
+
+// DeriveKeyFromPassword implements the ToolLogic interface.
+func (a2 *ARGON2ID) DeriveKeyFromPassword(password []byte, salt []byte) ([]byte, error) {
+	return argon2.IDKey(password, salt, a2.time, a2.memory, a2.parallelism, uint32(a2.Helper().DefaultSymmetricKeySize())), nil //nolint:gosec // key size is small
+}
+
+// PassDerivationCost implements the ToolLogic interface.
+// Every pass processes every 1 KiB memory block once.
+func (a2 *ARGON2ID) PassDerivationCost() int {
+	return int(a2.memory) * int(a2.time)
+}
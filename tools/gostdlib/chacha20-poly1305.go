@@ -13,7 +13,7 @@ func init() {
 		Info: &tools.ToolInfo{
 			Name:          "CHACHA20-POLY1305",
 			Purpose:       tools.PurposeIntegratedCipher,
-			Options:       []uint8{tools.OptionHasState},
+			Options:       []uint8{tools.OptionStreaming, tools.OptionHasState},
 			KeySize:       chacha20poly1305.KeySize, // 256 bit
 			NonceSize:     chacha20poly1305.NonceSize,
 			SecurityLevel: 128, // ChaCha20 is actually 256. Limiting to 128 for now because of Poly1305. TODO: do some more research on Poly1305
@@ -0,0 +1,221 @@
+package gostdlib
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+func init() {
+	ecdsaInfo := &tools.ToolInfo{
+		Purpose: tools.PurposeSigning,
+		Options: []uint8{tools.OptionNeedsManagedHasher},
+		Comment: "FIPS 186",
+		Author:  "NIST, 2009",
+	}
+
+	tools.Register(&tools.Tool{
+		Info: ecdsaInfo.With(&tools.ToolInfo{
+			Name:          "ECDSA-P256",
+			SecurityLevel: 128,
+		}),
+		Factory: func() tools.ToolLogic { return &ECDSA{curve: elliptic.P256()} },
+	})
+	tools.Register(&tools.Tool{
+		Info: ecdsaInfo.With(&tools.ToolInfo{
+			Name:          "ECDSA-P384",
+			SecurityLevel: 192,
+		}),
+		Factory: func() tools.ToolLogic { return &ECDSA{curve: elliptic.P384()} },
+	})
+	tools.Register(&tools.Tool{
+		Info: ecdsaInfo.With(&tools.ToolInfo{
+			Name:          "ECDSA-P521",
+			SecurityLevel: 256,
+		}),
+		Factory: func() tools.ToolLogic { return &ECDSA{curve: elliptic.P521()} },
+	})
+}
+
+// ECDSA implements the cryptographic interface for ECDSA signatures with NIST curves.
+// Keys are stored in the same format as the ECDH-P* tools.
+type ECDSA struct {
+	tools.ToolLogicBase
+	curve elliptic.Curve
+}
+
+// Sign implements the ToolLogic interface.
+func (ec *ECDSA) Sign(data, associatedData []byte, signet tools.SignetInt) ([]byte, error) {
+	privKey, ok := signet.PrivateKey().(*ecdsa.PrivateKey)
+	if !ok || privKey == nil {
+		return nil, tools.ErrInvalidKey
+	}
+
+	hashsum, err := ec.ManagedHashSum()
+	if err != nil {
+		return nil, err
+	}
+
+	return ecdsa.SignASN1(ec.Helper().Random(), privKey, hashsum)
+}
+
+// Verify implements the ToolLogic interface.
+func (ec *ECDSA) Verify(data, associatedData, signature []byte, signet tools.SignetInt) error {
+	pubKey, ok := signet.PublicKey().(*ecdsa.PublicKey)
+	if !ok || pubKey == nil {
+		return tools.ErrInvalidKey
+	}
+
+	hashsum, err := ec.ManagedHashSum()
+	if err != nil {
+		return err
+	}
+
+	if !ecdsa.VerifyASN1(pubKey, hashsum, signature) {
+		return errors.New("signature invalid")
+	}
+	return nil
+}
+
+// LoadKey implements the ToolLogic interface.
+func (ec *ECDSA) LoadKey(signet tools.SignetInt) error {
+	var pubKey *ecdsa.PublicKey
+	var privKey *ecdsa.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load public key
+	pointXData, err := c.GetNextBlock()
+	if err != nil {
+		return err
+	}
+	pointYData, err := c.GetNextBlock()
+	if err != nil {
+		return err
+	}
+	pubKey = &ecdsa.PublicKey{
+		Curve: ec.curve,
+		X:     new(big.Int).SetBytes(pointXData),
+		Y:     new(big.Int).SetBytes(pointYData),
+	}
+
+	// check public key
+	if _, err := pubKey.ECDH(); err != nil {
+		return tools.ErrInvalidKey
+	}
+
+	// load private key
+	if !public {
+		privKey = &ecdsa.PrivateKey{
+			PublicKey: *pubKey,
+			D:         new(big.Int).SetBytes(c.CompileData()),
+		}
+
+		// check private key
+		ecdhPrivKey, err := privKey.ECDH()
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+		ecdhPubKey, err := pubKey.ECDH()
+		if err != nil || !ecdhPrivKey.PublicKey().Equal(ecdhPubKey) {
+			return tools.ErrInvalidKey
+		}
+
+		signet.SetLoadedKeys(pubKey, privKey)
+		return nil
+	}
+
+	signet.SetLoadedKeys(pubKey, nil)
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (ec *ECDSA) StoreKey(signet tools.SignetInt) error {
+	pubKey, ok := signet.PublicKey().(*ecdsa.PublicKey)
+	if !ok || pubKey == nil {
+		return fmt.Errorf("public key of invalid type %T", signet.PublicKey())
+	}
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store public key
+	c.AppendAsBlock(pubKey.X.Bytes())
+	c.AppendAsBlock(pubKey.Y.Bytes())
+
+	// store private key
+	if !public {
+		ecPrivKey, ok := privKey.(*ecdsa.PrivateKey)
+		if !ok || ecPrivKey == nil {
+			return fmt.Errorf("private key of invalid type %T", privKey)
+		}
+		// Use the fixed size scalar encoding of the ECDH tools.
+		c.Append(ecPrivKey.D.FillBytes(make([]byte, (ec.curve.Params().BitSize+7)/8)))
+	}
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (ec *ECDSA) GenerateKey(signet tools.SignetInt) error {
+	privKey, err := ecdsa.GenerateKey(ec.curve, ec.Helper().Random())
+	if err != nil {
+		return err
+	}
+
+	signet.SetLoadedKeys(&privKey.PublicKey, privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface. This is currently ineffective, see known issues in the project's README.
+func (ec *ECDSA) BurnKey(signet tools.SignetInt) error {
+	// burn private key
+	privKey, ok := signet.PrivateKey().(*ecdsa.PrivateKey)
+	if ok && privKey != nil && privKey.D != nil {
+		privKey.D.SetInt64(0)
+	}
+
+	return nil
+}
+
+// ImportStdKeys implements the ToolLogic interface.
+func (ec *ECDSA) ImportStdKeys(signet tools.SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	ecPubKey, ok := pubKey.(*ecdsa.PublicKey)
+	if !ok || ecPubKey == nil || ecPubKey.Curve != ec.curve {
+		return tools.ErrInvalidKey
+	}
+	if privKey == nil {
+		signet.SetLoadedKeys(ecPubKey, nil)
+		return nil
+	}
+
+	ecPrivKey, ok := privKey.(*ecdsa.PrivateKey)
+	if !ok || ecPrivKey == nil {
+		return tools.ErrInvalidKey
+	}
+	signet.SetLoadedKeys(ecPubKey, ecPrivKey)
+	return nil
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (ec *ECDSA) ExportStdKeys(signet tools.SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	// The tool already uses the key types of the standard library.
+	return signet.PublicKey(), signet.PrivateKey(), nil
+}
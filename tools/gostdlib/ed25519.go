@@ -171,3 +171,28 @@ func (ed *Ed25519) BurnKey(signet tools.SignetInt) error {
 
 	return nil
 }
+
+// ImportStdKeys implements the ToolLogic interface.
+func (ed *Ed25519) ImportStdKeys(signet tools.SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	edPubKey, ok := pubKey.(ed25519.PublicKey)
+	if !ok || len(edPubKey) != ed25519.PublicKeySize {
+		return tools.ErrInvalidKey
+	}
+	if privKey == nil {
+		signet.SetLoadedKeys(edPubKey, nil)
+		return nil
+	}
+
+	edPrivKey, ok := privKey.(ed25519.PrivateKey)
+	if !ok || len(edPrivKey) != ed25519.PrivateKeySize {
+		return tools.ErrInvalidKey
+	}
+	signet.SetLoadedKeys(edPubKey, edPrivKey)
+	return nil
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (ed *Ed25519) ExportStdKeys(signet tools.SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	// The tool already uses the key types of the standard library.
+	return signet.PublicKey(), signet.PrivateKey(), nil
+}
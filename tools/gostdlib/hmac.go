@@ -13,6 +13,7 @@ func init() {
 			Name:    "HMAC",
 			Purpose: tools.PurposeMAC,
 			Options: []uint8{
+				tools.OptionStreaming,
 				tools.OptionNeedsDedicatedHasher,
 				tools.OptionHasState,
 			},
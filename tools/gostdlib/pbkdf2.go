@@ -45,3 +45,8 @@ func (pd *PBKDF2) DeriveKeyFromPassword(password []byte, salt []byte) ([]byte, e
 		pd.hashFactory,
 	), nil
 }
+
+// PassDerivationCost implements the ToolLogic interface.
+func (pd *PBKDF2) PassDerivationCost() int {
+	return pd.iterations
+}
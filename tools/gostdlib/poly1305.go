@@ -13,7 +13,7 @@ func init() {
 		Info: &tools.ToolInfo{
 			Name:          "POLY1305",
 			Purpose:       tools.PurposeMAC,
-			Options:       []uint8{tools.OptionHasState},
+			Options:       []uint8{tools.OptionStreaming, tools.OptionHasState},
 			KeySize:       32,
 			SecurityLevel: 128, // TODO: do some more research
 			Comment:       "RFC 7539",
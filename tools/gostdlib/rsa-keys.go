@@ -152,6 +152,31 @@ func burnBigInt(i *big.Int) {
 	}
 }
 
+// ImportStdKeys implements the ToolLogic interface.
+func (base *rsaBase) ImportStdKeys(signet tools.SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	rsaPubKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok || rsaPubKey == nil {
+		return tools.ErrInvalidKey
+	}
+	if privKey == nil {
+		signet.SetLoadedKeys(rsaPubKey, nil)
+		return nil
+	}
+
+	rsaPrivKey, ok := privKey.(*rsa.PrivateKey)
+	if !ok || rsaPrivKey == nil {
+		return tools.ErrInvalidKey
+	}
+	signet.SetLoadedKeys(rsaPubKey, rsaPrivKey)
+	return nil
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (base *rsaBase) ExportStdKeys(signet tools.SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	// The tool already uses the key types of the standard library.
+	return signet.PublicKey(), signet.PrivateKey(), nil
+}
+
 // SecurityLevel implements the ToolLogic interface.
 func (base *rsaBase) SecurityLevel(signet tools.SignetInt) (int, error) {
 	if signet == nil {
@@ -11,7 +11,7 @@ func init() {
 		Info: &tools.ToolInfo{
 			Name:          "SALSA20",
 			Purpose:       tools.PurposeCipher,
-			Options:       []uint8{tools.OptionHasState},
+			Options:       []uint8{tools.OptionStreaming, tools.OptionHasState},
 			KeySize:       32, // 265 bits
 			NonceSize:     8,  // 64 bits
 			SecurityLevel: 256,
@@ -38,3 +38,8 @@ type SCRYPT struct {
 func (sc *SCRYPT) DeriveKeyFromPassword(password []byte, salt []byte) ([]byte, error) {
 	return scrypt.Key(password, salt, sc.n, sc.r, sc.p, sc.Helper().DefaultSymmetricKeySize())
 }
+
+// PassDerivationCost implements the ToolLogic interface.
+func (sc *SCRYPT) PassDerivationCost() int {
+	return sc.n
+}
@@ -0,0 +1,52 @@
+package gostdlib
+
+import (
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/safing/jess/tools"
+)
+
+func init() {
+	tools.Register(&tools.Tool{
+		Info: &tools.ToolInfo{
+			Name:          "XCHACHA20-POLY1305",
+			Purpose:       tools.PurposeIntegratedCipher,
+			Options:       []uint8{tools.OptionStreaming, tools.OptionHasState},
+			KeySize:       chacha20poly1305.KeySize, // 256 bit
+			NonceSize:     chacha20poly1305.NonceSizeX,
+			SecurityLevel: 128, // See CHACHA20-POLY1305.
+			Comment:       "ChaCha20-Poly1305 with extended 192 bit nonce, draft-irtf-cfrg-xchacha",
+			Author:        "Scott Arciszewski, 2018, based on work by Daniel J. Bernstein",
+		},
+		Factory: func() tools.ToolLogic { return &XChaCha20Poly1305{} },
+	})
+}
+
+// XChaCha20Poly1305 implements the cryptographic interface for XChaCha20-Poly1305 encryption.
+// The extended nonce is large enough to be safely chosen at random for any number of messages.
+type XChaCha20Poly1305 struct {
+	ChaCha20Poly1305
+}
+
+// Setup implements the ToolLogic interface.
+func (xchapo *XChaCha20Poly1305) Setup() (err error) {
+	// get key
+	xchapo.key, err = xchapo.Helper().NewSessionKey()
+	if err != nil {
+		return err
+	}
+
+	// get nonce
+	xchapo.nonce, err = xchapo.Helper().NewSessionNonce()
+	if err != nil {
+		return err
+	}
+
+	// get aead interface
+	xchapo.aead, err = chacha20poly1305.NewX(xchapo.key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+// Package hpke provides encryption with Hybrid Public Key Encryption (HPKE) as specified in RFC 9180.
+//
+// The data of a letter is encrypted for its single recipient with a single-shot HPKE encryption (RFC 9180, Section 6.1)
+// in the base mode (HPKE-X25519) or the auth mode (HPKE-AUTH-X25519), with these parameters:
+//
+//	KEM:  DHKEM(X25519, HKDF-SHA256), 0x0020
+//	KDF:  HKDF-SHA256, 0x0001
+//	AEAD: ChaCha20Poly1305, 0x0003
+//	info: "jess" (see Info)
+//	aad:  the associated data of the letter, empty by default
+//	pt:   the data of the letter, without padding or compression
+//
+// The resulting encapsulated key ("enc", 32 bytes) is stored as the value of the recipient's seal and the ciphertext ("ct") as the data of the letter.
+// Letters can thus be produced and opened by any other HPKE implementation. See the jess package for the layout of the letter.
+package hpke
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/hpke"
+	"github.com/cloudflare/circl/kem"
+
+	"github.com/safing/jess/tools"
+	"github.com/safing/structures/container"
+)
+
+const (
+	// Info is the application supplied information of the HPKE key schedule.
+	Info = "jess"
+
+	// EncapsulatedKeySize is the size of the encapsulated key, which is the value of the recipient's seal.
+	EncapsulatedKeySize = 32
+)
+
+var (
+	suite  = hpke.NewSuite(hpke.KEM_X25519_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305)
+	scheme = hpke.KEM_X25519_HKDF_SHA256.Scheme()
+)
+
+func init() {
+	hpkeInfo := &tools.ToolInfo{
+		Purpose:       tools.PurposeKeyEncapsulation,
+		Options:       []uint8{tools.OptionSealsPayload},
+		SecurityLevel: 128,
+		Comment:       "RFC 9180, DHKEM(X25519, HKDF-SHA256), HKDF-SHA256, ChaCha20Poly1305",
+		Author:        "Richard Barnes et al., 2022",
+	}
+
+	tools.Register(&tools.Tool{
+		Info: hpkeInfo.With(&tools.ToolInfo{
+			Name: "HPKE-X25519",
+		}),
+		Factory: func() tools.ToolLogic { return &HPKE{} },
+	})
+	tools.Register(&tools.Tool{
+		Info: hpkeInfo.With(&tools.ToolInfo{
+			Name:    "HPKE-AUTH-X25519",
+			Options: []uint8{tools.OptionSealsPayload, tools.OptionAuthenticatesSender},
+		}),
+		Factory: func() tools.ToolLogic { return &HPKE{} },
+	})
+}
+
+// HPKE implements the cryptographic interface for encryption with HPKE in the base and auth modes.
+type HPKE struct {
+	tools.ToolLogicBase
+}
+
+// SealPayload implements the ToolLogic interface.
+func (h *HPKE) SealPayload(data, associatedData []byte, local, remote tools.SignetInt) (encapsulatedKey, ciphertext []byte, err error) {
+	pubKey, ok := remote.PublicKey().(kem.PublicKey)
+	if !ok {
+		return nil, nil, tools.ErrInvalidKey
+	}
+
+	sender, err := suite.NewSender(pubKey, []byte(Info))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// setup in base or auth mode
+	var sealer hpke.Sealer
+	if local != nil {
+		privKey, ok := local.PrivateKey().(kem.PrivateKey)
+		if !ok {
+			return nil, nil, tools.ErrInvalidKey
+		}
+		encapsulatedKey, sealer, err = sender.SetupAuth(h.Helper().Random(), privKey)
+	} else {
+		encapsulatedKey, sealer, err = sender.Setup(h.Helper().Random())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err = sealer.Seal(data, associatedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encapsulatedKey, ciphertext, nil
+}
+
+// OpenPayload implements the ToolLogic interface.
+func (h *HPKE) OpenPayload(encapsulatedKey, ciphertext, associatedData []byte, local, remote tools.SignetInt) ([]byte, error) {
+	privKey, ok := local.PrivateKey().(kem.PrivateKey)
+	if !ok {
+		return nil, tools.ErrInvalidKey
+	}
+	if len(encapsulatedKey) != EncapsulatedKeySize {
+		return nil, errors.New("invalid encapsulated key size")
+	}
+
+	receiver, err := suite.NewReceiver(privKey, []byte(Info))
+	if err != nil {
+		return nil, err
+	}
+
+	// setup in base or auth mode
+	var opener hpke.Opener
+	if remote != nil {
+		pubKey, ok := remote.PublicKey().(kem.PublicKey)
+		if !ok {
+			return nil, tools.ErrInvalidKey
+		}
+		opener, err = receiver.SetupAuth(encapsulatedKey, pubKey)
+	} else {
+		opener, err = receiver.Setup(encapsulatedKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return opener.Open(ciphertext, associatedData)
+}
+
+// LoadKey implements the ToolLogic interface.
+// Keys are stored in the same format as ECDH-X25519 keys.
+func (h *HPKE) LoadKey(signet tools.SignetInt) error {
+	var pubKey crypto.PublicKey
+	var privKey crypto.PrivateKey
+
+	key, public := signet.GetStoredKey()
+	c := container.New(key)
+
+	// check serialization version
+	version, err := c.GetNextN8()
+	if err != nil || version != 1 {
+		return tools.ErrInvalidKey
+	}
+
+	// load public key
+	data, err := c.Get(scheme.PublicKeySize())
+	if err != nil {
+		return tools.ErrInvalidKey
+	}
+	pubKey, err = scheme.UnmarshalBinaryPublicKey(data)
+	if err != nil {
+		return tools.ErrInvalidKey
+	}
+
+	// load private key
+	if !public {
+		data, err = c.Get(scheme.PrivateKeySize())
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+		privKey, err = scheme.UnmarshalBinaryPrivateKey(data)
+		if err != nil {
+			return tools.ErrInvalidKey
+		}
+	}
+
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// StoreKey implements the ToolLogic interface.
+func (h *HPKE) StoreKey(signet tools.SignetInt) error {
+	pubKey := signet.PublicKey()
+	privKey := signet.PrivateKey()
+	public := privKey == nil
+
+	// create storage with serialization version
+	c := container.New()
+	c.AppendNumber(1)
+
+	// store keys
+	kemPubKey, ok := pubKey.(kem.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key of invalid type %T", pubKey)
+	}
+	pubKeyData, err := kemPubKey.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	c.Append(pubKeyData)
+	if !public {
+		kemPrivKey, ok := privKey.(kem.PrivateKey)
+		if !ok {
+			return fmt.Errorf("private key of invalid type %T", privKey)
+		}
+		privKeyData, err := kemPrivKey.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		c.Append(privKeyData)
+	}
+
+	signet.SetStoredKey(c.CompileData(), public)
+	return nil
+}
+
+// GenerateKey implements the ToolLogic interface.
+func (h *HPKE) GenerateKey(signet tools.SignetInt) error {
+	seed := make([]byte, scheme.SeedSize())
+	_, err := io.ReadFull(h.Helper().Random(), seed)
+	if err != nil {
+		return err
+	}
+	defer h.Helper().Burn(seed)
+
+	pubKey, privKey := scheme.DeriveKeyPair(seed)
+	signet.SetLoadedKeys(pubKey, privKey)
+	return nil
+}
+
+// BurnKey implements the ToolLogic interface.
+// The keys of CIRCL are opaque and cannot be burned, see known issues in the project's README.
+func (h *HPKE) BurnKey(signet tools.SignetInt) error {
+	return nil
+}
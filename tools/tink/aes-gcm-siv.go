@@ -0,0 +1,78 @@
+// Package tink provides tools based on the cryptographic primitives of Tink.
+package tink
+
+import (
+	"github.com/tink-crypto/tink-go/v2/aead/subtle"
+
+	"github.com/safing/jess/tools"
+)
+
+func init() {
+	aesGcmSivInfo := &tools.ToolInfo{
+		Purpose: tools.PurposeIntegratedCipher,
+		Options: []uint8{tools.OptionStreaming, tools.OptionHasState},
+		Comment: "nonce-misuse-resistant AES-GCM, RFC 8452, with a random nonce prepended to the ciphertext",
+		Author:  "Shay Gueron, Adam Langley and Yehuda Lindell, 2019",
+	}
+	aesGcmSivFactory := func() tools.ToolLogic { return &AesGCMSIV{} }
+
+	tools.Register(&tools.Tool{
+		Info: aesGcmSivInfo.With(&tools.ToolInfo{
+			Name:          "AES128-GCM-SIV",
+			KeySize:       16, // 128 bits
+			SecurityLevel: 128,
+		}),
+		Factory: aesGcmSivFactory,
+	})
+	tools.Register(&tools.Tool{
+		Info: aesGcmSivInfo.With(&tools.ToolInfo{
+			Name:          "AES256-GCM-SIV",
+			KeySize:       32, // 256 bits
+			SecurityLevel: 256,
+		}),
+		Factory: aesGcmSivFactory,
+	})
+}
+
+// AesGCMSIV implements the cryptographic interface for AES-GCM-SIV encryption.
+// The AEAD of Tink chooses a random nonce for every encryption and prepends it to the ciphertext, so the tool does not use a session nonce.
+type AesGCMSIV struct {
+	tools.ToolLogicBase
+	aead *subtle.AESGCMSIV
+	key  []byte
+}
+
+// Setup implements the ToolLogic interface.
+func (aessiv *AesGCMSIV) Setup() (err error) {
+	// get key
+	aessiv.key, err = aessiv.Helper().NewSessionKey()
+	if err != nil {
+		return err
+	}
+
+	// get aead interface
+	aessiv.aead, err = subtle.NewAESGCMSIV(aessiv.key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reset implements the ToolLogic interface.
+func (aessiv *AesGCMSIV) Reset() error {
+	// clean up keys
+	aessiv.Helper().Burn(aessiv.key)
+
+	return nil
+}
+
+// AuthenticatedEncrypt implements the ToolLogic interface.
+func (aessiv *AesGCMSIV) AuthenticatedEncrypt(data, associatedData []byte) ([]byte, error) {
+	return aessiv.aead.Encrypt(data, associatedData)
+}
+
+// AuthenticatedDecrypt implements the ToolLogic interface.
+func (aessiv *AesGCMSIV) AuthenticatedDecrypt(data, associatedData []byte) ([]byte, error) {
+	return aessiv.aead.Decrypt(data, associatedData)
+}
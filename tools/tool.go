@@ -75,8 +75,7 @@ const (
 const (
 	// Operation Types.
 
-	// OptionStreaming declares that the tool can work with streaming data and might be given a io.Reader and io.Writer instead of just a []byte slice.
-	// TODO: Implementation pending.
+	// OptionStreaming declares that the tool can work with streaming data. Streams are processed in chunks and the tool is set up and reset for every chunk with fresh key material.
 	OptionStreaming uint8 = iota + 1
 
 	// Needs.
@@ -95,6 +94,16 @@ const (
 
 	// OptionHasState declares that the tool has an internal state and requires the setup and reset routines to be run before/after usage. KeyDerivation tools do not have to declare this, their state is handled separately.
 	OptionHasState
+
+	// OptionHasParameters declares that the tool takes parameters from its tool ID, eg. ARGON2ID(m=65536,t=3,p=4). The parameters are passed to SetParameters before the tool is initialized.
+	OptionHasParameters
+
+	// OptionAuthenticatesSender declares that the tool that seals the payload also authenticates the sender. It is only valid together with OptionSealsPayload. The sender's signet of the same scheme must be supplied as a sender and is passed to SealPayload and OpenPayload.
+	OptionAuthenticatesSender
+
+	// OptionSealsPayload declares that the key encapsulation tool also encrypts and authenticates the data itself, with the key it encapsulates for the recipient, as with HPKE. It provides Confidentiality and Integrity and must be the only tool of the suite. Letters are sealed to exactly one recipient and do not support padding or compression.
+	// Tools declaring this implement SealPayload and OpenPayload instead of EncapsulateKey and UnwrapKey.
+	OptionSealsPayload
 )
 
 // HasOption returns whether the *ToolInfo has the given option.
@@ -182,6 +191,12 @@ func (ti *ToolInfo) FormatOptions() string {
 			s = append(s, "NeedsDefaultKeySize")
 		case OptionHasState:
 			s = append(s, "HasState")
+		case OptionHasParameters:
+			s = append(s, "HasParameters")
+		case OptionAuthenticatesSender:
+			s = append(s, "AuthenticatesSender")
+		case OptionSealsPayload:
+			s = append(s, "SealsPayload")
 		default:
 			s = append(s, "UNKNOWN")
 		}
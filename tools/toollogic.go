@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"crypto"
 	"errors"
 
 	"github.com/safing/jess/hashtools"
@@ -41,6 +42,14 @@ type ToolLogic interface {
 	// Must be overridden by tools that declare FeaturePassDerivation.
 	DeriveKeyFromPassword(password []byte, salt []byte) ([]byte, error)
 
+	// PassDerivationCost returns the cost of deriving a key from a password, as the number of basic operations an attacker has to do per guess.
+	// Should be overridden by tools that declare FeaturePassDerivation.
+	PassDerivationCost() int
+
+	// SetParameters sets the parameters from the tool ID.
+	// Must be overridden by tools that declare OptionHasParameters.
+	SetParameters(params string) error
+
 	// InitKeyDerivation initializes the key generation.
 	// Must be overridden by tools that declare FeatureKeyDerivation.
 	InitKeyDerivation(nonce []byte, material ...[]byte) error
@@ -67,6 +76,14 @@ type ToolLogic interface {
 	// Must be overridden by tools that declare FeatureKeyEncapsulation.
 	UnwrapKey(wrappedKey []byte, local SignetInt) ([]byte, error)
 
+	// SealPayload encrypts and authenticates the data and the associated data for the given Signet (remote public key) and returns the encapsulated key and the ciphertext. If local is not nil, the data is also authenticated to be from the given Signet (local private key).
+	// Must be overridden by tools that declare OptionSealsPayload.
+	SealPayload(data, associatedData []byte, local, remote SignetInt) (encapsulatedKey, ciphertext []byte, err error)
+
+	// OpenPayload decrypts the ciphertext with the encapsulated key using the given Signet (local private key) and authenticates it together with the associated data. If remote is not nil, it also verifies that the data is from the given Signet (remote public key).
+	// Must be overridden by tools that declare OptionSealsPayload.
+	OpenPayload(encapsulatedKey, ciphertext, associatedData []byte, local, remote SignetInt) ([]byte, error)
+
 	// Encryption and Authentication
 
 	// Encrypt encrypts the given data.
@@ -122,6 +139,16 @@ type ToolLogic interface {
 	// Implementations of this are currently ineffective, see known issues in the project's README.
 	BurnKey(signet SignetInt) error
 
+	// ImportStdKeys takes keys in the key types of the standard library, as returned by the crypto/x509 package, and sets them as the loaded keys (`Loaded*`) of the Signet. The private key may be nil. Keys of an unsuitable type must be rejected with ErrInvalidKey.
+	// Must work with a static (no Setup()) ToolLogic.
+	// May be overridden by tools whose keys can be imported from standard formats, such as PEM.
+	ImportStdKeys(signet SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error
+
+	// ExportStdKeys returns the loaded keys (`Loaded*`) of the Signet in the key types of the standard library, as accepted by the crypto/x509 package. The private key is nil, if the Signet has no private key loaded.
+	// Must work with a static (no Setup()) ToolLogic.
+	// May be overridden by tools whose keys can be exported to standard formats, such as PEM.
+	ExportStdKeys(signet SignetInt) (crypto.PublicKey, crypto.PrivateKey, error)
+
 	// SecurityLevel returns the security level (approximate attack complexity as 2^n) of the given tool.
 	// May be overridden if needed for custom calculation (ie. based on actual key size in signet or hash tool). Init() will be called before SecurityLevel() is called.
 	SecurityLevel(signet SignetInt) (int, error)
@@ -210,6 +237,16 @@ func (tlb *ToolLogicBase) DeriveKeyFromPassword(password []byte, salt []byte) ([
 	return nil, ErrNotImplemented
 }
 
+// PassDerivationCost implements the ToolLogic interface.
+func (tlb *ToolLogicBase) PassDerivationCost() int {
+	return 1
+}
+
+// SetParameters implements the ToolLogic interface.
+func (tlb *ToolLogicBase) SetParameters(params string) error {
+	return ErrNotImplemented
+}
+
 // InitKeyDerivation implements the ToolLogic interface.
 func (tlb *ToolLogicBase) InitKeyDerivation(nonce []byte, material ...[]byte) error {
 	return ErrNotImplemented
@@ -240,6 +277,16 @@ func (tlb *ToolLogicBase) UnwrapKey(wrappedKey []byte, local SignetInt) ([]byte,
 	return nil, ErrNotImplemented
 }
 
+// SealPayload implements the ToolLogic interface.
+func (tlb *ToolLogicBase) SealPayload(data, associatedData []byte, local, remote SignetInt) (encapsulatedKey, ciphertext []byte, err error) {
+	return nil, nil, ErrNotImplemented
+}
+
+// OpenPayload implements the ToolLogic interface.
+func (tlb *ToolLogicBase) OpenPayload(encapsulatedKey, ciphertext, associatedData []byte, local, remote SignetInt) ([]byte, error) {
+	return nil, ErrNotImplemented
+}
+
 // Encrypt implements the ToolLogic interface.
 func (tlb *ToolLogicBase) Encrypt(data []byte) ([]byte, error) {
 	return nil, ErrNotImplemented
@@ -294,3 +341,13 @@ func (tlb *ToolLogicBase) GenerateKey(signet SignetInt) error {
 func (tlb *ToolLogicBase) BurnKey(signet SignetInt) error {
 	return ErrNotImplemented
 }
+
+// ImportStdKeys implements the ToolLogic interface.
+func (tlb *ToolLogicBase) ImportStdKeys(signet SignetInt, pubKey crypto.PublicKey, privKey crypto.PrivateKey) error {
+	return ErrNotImplemented
+}
+
+// ExportStdKeys implements the ToolLogic interface.
+func (tlb *ToolLogicBase) ExportStdKeys(signet SignetInt) (crypto.PublicKey, crypto.PrivateKey, error) {
+	return nil, nil, ErrNotImplemented
+}
@@ -212,6 +212,23 @@ func TestSignetHandling(t *testing.T) {
 					t.Fatalf("failed to generate test key: %s", err)
 				}
 
+				if tool.Info.HasOption(tools.OptionSealsPayload) {
+					encapsulatedKey, ciphertext, err := toolLogic.SealPayload(origKey, nil, nil, loadedRcpt)
+					if err != nil {
+						t.Fatalf("failed to seal payload with %s: %s", tool.Info.Name, err)
+					}
+
+					opened, err := toolLogic.OpenPayload(encapsulatedKey, ciphertext, nil, loadedSignet, nil)
+					if err != nil {
+						t.Fatalf("failed to open payload with %s: %s", tool.Info.Name, err)
+					}
+
+					if !bytes.Equal(origKey, opened) {
+						t.Fatalf("original and opened payload with %s do not match, got:\norig: %v\nopened: %v", tool.Info.Name, origKey, opened)
+					}
+					break
+				}
+
 				wrappedKey, err := toolLogic.EncapsulateKey(origKey, loadedRcpt)
 				if err != nil {
 					t.Fatalf("failed to encapsulate key with %s: %s", tool.Info.Name, err)
@@ -249,3 +266,107 @@ func TestSignetHandling(t *testing.T) {
 		}
 	}
 }
+
+func TestNistKeyCompatibility(t *testing.T) {
+	t.Parallel()
+
+	// Keys of the ECDH and ECDSA tools for the same curve share their serialization.
+	for _, curve := range []string{"P256", "P384", "P521"} {
+		ecdhSignet, err := GenerateSignet("ECDH-"+curve, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ecdhSignet.StoreKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ecdsaSignet := &Signet{
+			Version: 1,
+			Scheme:  "ECDSA-" + curve,
+			Key:     ecdhSignet.Key,
+		}
+		err = ecdsaSignet.LoadKey()
+		if err != nil {
+			t.Fatalf("failed to load ECDH-%s key as ECDSA-%s key: %s", curve, curve, err)
+		}
+		ecdsaSignet.Key = nil
+		err = ecdsaSignet.StoreKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ecdsaSignet.Key, ecdhSignet.Key) {
+			t.Errorf("ECDSA-%s stores the key differently than ECDH-%s", curve, curve)
+		}
+	}
+}
+
+func TestToolParameters(t *testing.T) {
+	t.Parallel()
+
+	newPasswordSession := func(toolID, password string) (*Session, error) {
+		e := &Envelope{
+			Version: 1,
+			suite: &Suite{
+				ID:            "__unit_test_suite__tool_parameters",
+				Tools:         []string{toolID, "BLAKE3-KDF", "CHACHA20-POLY1305"},
+				Provides:      NewRequirements(),
+				SecurityLevel: 128,
+			},
+			Secrets: []*Signet{{
+				Version: 1,
+				Scheme:  SignetSchemePassword,
+				Key:     []byte(password),
+			}},
+		}
+		return newSession(e)
+	}
+
+	// valid parameters
+	s, err := newPasswordSession("ARGON2ID(m=1024,t=2,p=1)", testPassword1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := s.passDerivator.PassDerivationCost(); cost != 2048 {
+		t.Fatalf("unexpected cost %d", cost)
+	}
+	letter, err := s.Close([]byte(testData1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := s.Open(letter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testData1 {
+		t.Fatal("data mismatch")
+	}
+
+	// invalid parameters
+	for _, toolID := range []string{
+		"ARGON2ID(m=1024,t=0,p=1)",
+		"ARGON2ID(m=4,t=1,p=1)",
+		"ARGON2ID(m=1024,t=1,p=256)",
+		"ARGON2ID(m=4294967295,t=1,p=1)",
+		"ARGON2ID(m=1024,t=4294967295,p=1)",
+		"ARGON2ID(m=1024,t=1,p=64)",
+		"ARGON2ID(x=1)",
+		"ARGON2ID(m)",
+	} {
+		_, err = newPasswordSession(toolID, testPassword1)
+		if err == nil {
+			t.Errorf("%s should fail", toolID)
+		}
+	}
+
+	// cost counts towards the password security level
+	password := "AVWHBwmFGtLMGhYfPkcy" // 113 bits without cost
+	_, err = newPasswordSession("ARGON2ID(m=1024,t=1,p=1)", password)
+	if err == nil {
+		t.Error("password with low cost should not reach the minimum security level")
+	}
+	_, err = newPasswordSession("ARGON2ID(m=1048576,t=1,p=1)", password)
+	if err != nil {
+		t.Errorf("password with high cost should reach the minimum security level: %s", err)
+	}
+}
@@ -79,7 +79,7 @@ func (mts *MemTrustStore) SelectSignets(filter uint8, schemes ...string) ([]*Sig
 	for _, signet := range mts.storage {
 		// check signet scheme
 		if len(schemes) > 0 && !stringInSlice(signet.Scheme, schemes) {
-			return nil, nil
+			continue
 		}
 
 		// check type filter
@@ -0,0 +1,48 @@
+package jess
+
+import (
+	"testing"
+)
+
+func TestMemTrustStoreSelectSignets(t *testing.T) {
+	t.Parallel()
+
+	trustStore := NewMemTrustStore()
+	for _, signet := range []*Signet{
+		{ID: "a", Scheme: "ECDH-X25519"},
+		{ID: "b", Scheme: "Ed25519"},
+		{ID: "c", Scheme: "Ed25519", Public: true},
+		{ID: "d", Scheme: "ECDH-X25519", Public: true},
+	} {
+		err := trustStore.StoreSignet(signet)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		filter   uint8
+		schemes  []string
+		expected int
+	}{
+		{FilterAny, nil, 4},
+		{FilterSignetOnly, nil, 2},
+		{FilterRecipientOnly, nil, 2},
+		{FilterAny, []string{"Ed25519"}, 2},
+		{FilterSignetOnly, []string{"Ed25519"}, 1},
+		{FilterRecipientOnly, []string{"ECDH-X25519"}, 1},
+		{FilterAny, []string{"Ed25519", "ECDH-X25519"}, 4},
+		{FilterAny, []string{"Ed448"}, 0},
+	} {
+		// Map iteration order is random, so select multiple times.
+		for i := 0; i < 10; i++ {
+			selection, err := trustStore.SelectSignets(test.filter, test.schemes...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(selection) != test.expected {
+				t.Fatalf("filter %d with schemes %v: expected %d signets, got %d", test.filter, test.schemes, test.expected, len(selection))
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package truststores
+
+import (
+	"fmt"
+
+	"github.com/safing/jess"
+)
+
+// StoreCertifiedSignet stores the public version of the given signet in the trust store, if it is certified by a signer already in the trust store.
+// This makes the recipient trusted through the certification of a trusted key.
+func StoreCertifiedSignet(trustStore ExtendedTrustStore, signet *jess.Signet) error {
+	err := signet.Verify(trustStore)
+	if err != nil {
+		return err
+	}
+
+	rcpt, err := signet.AsRecipient()
+	if err != nil {
+		return fmt.Errorf("failed to get recipient of signet %s: %w", signet.ID, err)
+	}
+	return trustStore.StoreSignet(rcpt)
+}
@@ -108,15 +108,15 @@ func (dts *DirTrustStore) SelectSignets(filter uint8, schemes ...string) ([]*jes
 
 		signet, err := LoadSignetFromFile(path)
 		if err != nil {
-			// add failed signet to list
-			selection = append(selection, &jess.Signet{
+			// Add failed signet as placeholder and continue with the others.
+			// As its scheme is unknown, it is only selected when not filtering by scheme.
+			signet = &jess.Signet{
 				Info: &jess.SignetInfo{
 					Name: "[failed to load]",
 				},
 				ID:     strings.Split(filepath.Base(path), ".")[0],
 				Public: strings.HasSuffix(path, recipientSuffix),
-			})
-			return err
+			}
 		}
 
 		// check signet scheme
@@ -1,8 +1,64 @@
 package truststores
 
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safing/jess"
+	_ "github.com/safing/jess/tools/all"
+)
+
 func init() {
 	// interface compliance test
 	var testDirTrustStore ExtendedTrustStore
 	testDirTrustStore, _ = NewDirTrustStore("/tmp")
 	_ = testDirTrustStore
 }
+
+func TestDirTrustStoreSelectSignets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	trustStore, err := NewDirTrustStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// store a valid signet and an unloadable one
+	signet, err := jess.GenerateSignet("Ed25519", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signet.ID = "valid"
+	err = signet.StoreKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = trustStore.StoreSignet(signet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "broken"+signetSuffix), []byte("broken"), 0o0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unloadable signets are listed, but do not abort the selection
+	selection, err := trustStore.SelectSignets(jess.FilterAny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selection) != 2 {
+		t.Fatalf("expected 2 signets, got %d", len(selection))
+	}
+
+	// unloadable signets are not selected when filtering by scheme
+	selection, err = trustStore.SelectSignets(jess.FilterSignetOnly, "Ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selection) != 1 || selection[0].ID != "valid" {
+		t.Fatalf("expected only the valid signet, got %+v", selection)
+	}
+}